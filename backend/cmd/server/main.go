@@ -2,10 +2,12 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -13,9 +15,11 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	"github.com/RJ-Bond/js-monitoring/internal/agent"
 	"github.com/RJ-Bond/js-monitoring/internal/api"
 	"github.com/RJ-Bond/js-monitoring/internal/bot"
 	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/leader"
 	"github.com/RJ-Bond/js-monitoring/internal/models"
 	"github.com/RJ-Bond/js-monitoring/internal/poller"
 )
@@ -23,6 +27,12 @@ import (
 func main() {
 	_ = godotenv.Load()
 
+	// Agent mode: poll from another region without a DB and push results to the central API
+	if apiURL := os.Getenv("AGENT_API_URL"); apiURL != "" {
+		runAgent(apiURL, os.Getenv("AGENT_TOKEN"))
+		return
+	}
+
 	cfg := database.Config{
 		Host:     env("DB_HOST", "localhost"),
 		Port:     env("DB_PORT", "3306"),
@@ -51,12 +61,6 @@ func main() {
 
 	go api.WSHub.Run()
 
-	p := poller.New(func(serverID uint, status *models.ServerStatus) {
-		api.WSHub.BroadcastUpdate(serverID, status)
-	})
-	p.Start()
-	defer p.Stop()
-
 	e := echo.New()
 	e.HideBanner = true
 	e.Use(middleware.Logger())
@@ -98,13 +102,18 @@ func main() {
 	// ── Public read routes ────────────────────────────────────────────────────
 	v1.GET("/stats", api.GetStats)
 	v1.GET("/ws", api.HandleWebSocket)
-	v1.GET("/rcon", api.HandleRCON)
+	v1.GET("/rcon", api.HandleRCON, api.WSQueryAuth, api.JWTMiddleware)
+	v1.GET("/games", api.GetGames)
 	v1.GET("/servers", api.GetServers)
 	v1.GET("/servers/:id", api.GetServer)
 	v1.GET("/servers/:id/history", api.GetServerHistory)
-	v1.GET("/servers/:id/players", api.GetServerPlayers)
+	v1.GET("/servers/:id/players", api.GetServerPlayers, api.OptionalAuth)
 	v1.GET("/servers/:id/leaderboard", api.GetLeaderboard)
 	v1.GET("/servers/:id/uptime", api.GetUptime)
+	v1.GET("/servers/:id/favicon", api.GetServerFavicon)
+	v1.GET("/servers/:id/regions", api.GetServerRegions)
+	v1.GET("/servers/:id/incidents", api.GetServerIncidents)
+	v1.GET("/servers/:id/maintenance", api.GetMaintenanceWindows)
 	v1.GET("/news", api.GetNews)
 	v1.GET("/news.rss", api.GetNewsRSS)
 	v1.POST("/news/:id/view", api.TrackView)
@@ -132,11 +141,23 @@ func main() {
 	authG.POST("/reset-password", api.ResetPassword)
 	authG.POST("/2fa", api.Verify2FA)
 
+	// ── Remote polling agents (agent token, not JWT) ──────────────────────────
+	agentG := v1.Group("/agent", api.AgentAuth)
+	agentG.GET("/targets", api.AgentGetTargets)
+	agentG.POST("/report", api.AgentPostReport)
+
 	// ── JWT-protected write routes ────────────────────────────────────────────
 	protected := v1.Group("", api.JWTMiddleware)
 	protected.POST("/servers", api.CreateServer)
+	protected.POST("/servers/probe", api.ProbeServer, probeRateLimit())
 	protected.PUT("/servers/:id", api.UpdateServer)
 	protected.DELETE("/servers/:id", api.DeleteServer)
+	protected.POST("/servers/:id/incidents/:incidentID/ack", api.AckIncident)
+	protected.POST("/servers/:id/maintenance", api.CreateMaintenanceWindow)
+	protected.DELETE("/servers/:id/maintenance/:windowID", api.DeleteMaintenanceWindow)
+	protected.GET("/servers/:id/rcon/access", api.GetRCONAccess)
+	protected.POST("/servers/:id/rcon/access", api.GrantRCONAccess)
+	protected.DELETE("/servers/:id/rcon/access/:userID", api.RevokeRCONAccess)
 	protected.GET("/profile", api.GetProfile)
 	protected.PUT("/profile", api.UpdateProfile)
 	protected.PUT("/profile/avatar", api.UpdateAvatar)
@@ -170,6 +191,10 @@ func main() {
 	admin.PUT("/settings", api.UpdateSettings)
 	admin.GET("/alerts/:serverID", api.GetAlertConfig)
 	admin.PUT("/alerts/:serverID", api.UpdateAlertConfig)
+	admin.GET("/alerts/:serverID/rules", api.GetAlertRules)
+	admin.POST("/alerts/:serverID/rules", api.CreateAlertRule)
+	admin.PUT("/alerts/:serverID/rules/:ruleID", api.UpdateAlertRule)
+	admin.DELETE("/alerts/:serverID/rules/:ruleID", api.DeleteAlertRule)
 	admin.POST("/users/:id/reset-token", api.GenerateResetToken)
 	admin.GET("/audit", api.GetAuditLog)
 	admin.GET("/discord/:serverID", api.GetDiscordConfig)
@@ -178,6 +203,7 @@ func main() {
 	admin.GET("/export/servers.csv", api.ExportServers)
 	admin.GET("/export/players.csv", api.ExportPlayers)
 	admin.GET("/export/audit.csv", api.ExportAudit)
+	admin.GET("/reports/sla", api.GetSLAReport)
 	admin.POST("/users/bulk", api.AdminBulkUsers)
 	admin.POST("/servers/bulk", api.AdminBulkServers)
 	admin.POST("/news/webhook/test", api.TestNewsWebhook)
@@ -187,6 +213,11 @@ func main() {
 	admin.POST("/restore", api.RestoreBackup)
 	admin.GET("/dashboard", api.GetDashboard)
 	admin.GET("/health", api.GetSystemHealth)
+	admin.GET("/incidents", api.AdminGetIncidents)
+	admin.GET("/agents", api.AdminGetAgents)
+	admin.POST("/agents", api.AdminCreateAgent)
+	admin.PUT("/agents/:id", api.AdminUpdateAgent)
+	admin.DELETE("/agents/:id", api.AdminDeleteAgent)
 	// V Rising moderation
 	admin.GET("/vrising/:serverID/bans", api.GetVRisingBans)
 	admin.POST("/vrising/:serverID/mod-command", api.QueueVRisingModCommand)
@@ -212,7 +243,40 @@ func main() {
 		cancel()
 	}()
 
-	// ── Start bots (if configured) ────────────────────────────────────────────
+	// ── Leader election: poller and bots run on one replica only ──────────────
+	// Every replica serves the HTTP API; followers relay the leader's status
+	// updates from the DB to their own WebSocket clients.
+	electionDone := make(chan struct{})
+	go func() {
+		defer close(electionDone)
+		leader.New("poller").Run(ctx, runLeader, api.RelayStatusUpdates)
+	}()
+
+	port := env("PORT", "8080")
+	log.Printf("Starting server on :%s", port)
+
+	go func() {
+		<-ctx.Done()
+		shutCtx, shutCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer shutCancel()
+		_ = e.Shutdown(shutCtx)
+	}()
+
+	if err := e.Start(":" + port); err != nil && err.Error() != "http: Server closed" {
+		log.Printf("Server stopped: %v", err)
+	}
+	<-electionDone
+}
+
+// runLeader runs the poller and bots while this replica holds the leader lease
+func runLeader(ctx context.Context) {
+	p := poller.New(func(serverID uint, status *models.ServerStatus) {
+		api.WSHub.BroadcastUpdate(serverID, status)
+	})
+	p.Start(ctx)
+	api.SetPoller(p)
+
+	var wg sync.WaitGroup
 	var settings models.SiteSettings
 	if database.DB.First(&settings, 1).Error == nil {
 		if settings.DiscordBotToken != "" {
@@ -222,7 +286,9 @@ func main() {
 				os.Setenv("HTTPS_PROXY", settings.DiscordProxy)
 				log.Printf("[discord-bot] using proxy from DB settings")
 			}
+			wg.Add(1)
 			go func() {
+				defer wg.Done()
 				b, err := bot.NewDiscordBot(token, appURL)
 				if err != nil {
 					log.Printf("[discord-bot] init error: %v", err)
@@ -234,23 +300,28 @@ func main() {
 		if settings.NewsTGBotToken != "" {
 			tgToken := settings.NewsTGBotToken
 			appURL := settings.AppURL
-			go bot.NewTelegramPoller(tgToken, appURL).Start(ctx)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				bot.NewTelegramPoller(tgToken, appURL).Start(ctx)
+			}()
 		}
 	}
 
-	port := env("PORT", "8080")
-	log.Printf("Starting server on :%s", port)
-
-	go func() {
-		<-ctx.Done()
-		shutCtx, shutCancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer shutCancel()
-		_ = e.Shutdown(shutCtx)
-	}()
+	<-ctx.Done()
+	api.SetPoller(nil)
+	p.Wait()
+	wg.Wait()
+}
 
-	if err := e.Start(":" + port); err != nil && err.Error() != "http: Server closed" {
-		log.Printf("Server stopped: %v", err)
+// runAgent runs the remote polling agent until SIGINT/SIGTERM
+func runAgent(apiURL, token string) {
+	if token == "" {
+		log.Fatal("AGENT_TOKEN is required in agent mode")
 	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	agent.New(apiURL, token).Run(ctx)
 }
 
 func env(key, fallback string) string {
@@ -259,3 +330,21 @@ func env(key, fallback string) string {
 	}
 	return fallback
 }
+
+// probeRateLimit limits server probes per user: every probe fans out to all
+// protocols, so the global per-IP limit alone would still allow port scanning.
+func probeRateLimit() echo.MiddlewareFunc {
+	tooMany := func(c echo.Context) error {
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "too many probe requests"})
+	}
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(
+			middleware.RateLimiterMemoryStoreConfig{Rate: 0.2, Burst: 5, ExpiresIn: 10 * time.Minute},
+		),
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			return fmt.Sprint(c.Get("user_id")), nil
+		},
+		ErrorHandler: func(c echo.Context, err error) error { return tooMany(c) },
+		DenyHandler:  func(c echo.Context, id string, err error) error { return tooMany(c) },
+	})
+}
@@ -0,0 +1,240 @@
+// Package agent implements the remote polling agent: the same binary started with
+// AGENT_API_URL and AGENT_TOKEN polls servers with the internal/poller protocols
+// from its own region and pushes the results to the central API.
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/poller"
+)
+
+// TokenHeader carries the agent token on every request to the central API.
+const TokenHeader = "X-Agent-Token"
+
+const (
+	targetsRefresh = 2 * time.Minute
+	reportFlush    = 10 * time.Second
+	dispatchTick   = 1 * time.Second
+	workerCount    = 16
+	// maxPending caps buffered reports while the central API is unreachable.
+	maxPending = 10000
+)
+
+// Target is a server the agent should poll.
+type Target struct {
+	ServerID uint   `json:"server_id"`
+	GameType string `json:"game_type"`
+	IP       string `json:"ip"`
+	Port     uint16 `json:"port"`     // query port
+	Interval int    `json:"interval"` // seconds
+}
+
+// Report is the outcome of one poll from the agent's vantage point.
+type Report struct {
+	ServerID      uint      `json:"server_id"`
+	OnlineStatus  bool      `json:"online_status"`
+	PlayersNow    int       `json:"players_now"`
+	PingMS        int       `json:"ping_ms"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	CheckedAt     time.Time `json:"checked_at"`
+}
+
+// Agent polls the targets assigned by the central API.
+type Agent struct {
+	apiURL string
+	token  string
+	client *http.Client
+
+	mu      sync.Mutex
+	targets map[uint]Target
+	nextDue map[uint]time.Time
+	pending []Report
+}
+
+// New creates an agent reporting to apiURL (e.g. https://monitor.example.com).
+func New(apiURL, token string) *Agent {
+	return &Agent{
+		apiURL:  strings.TrimRight(apiURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 15 * time.Second},
+		targets: make(map[uint]Target),
+		nextDue: make(map[uint]time.Time),
+	}
+}
+
+// Run polls until ctx is cancelled, then flushes the remaining reports.
+func (a *Agent) Run(ctx context.Context) {
+	if err := a.refreshTargets(ctx); err != nil {
+		log.Printf("[agent] fetch targets: %v", err)
+	}
+
+	jobs := make(chan Target, workerCount*4)
+	var wg sync.WaitGroup
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				a.poll(ctx, t)
+			}
+		}()
+	}
+
+	dispatch := time.NewTicker(dispatchTick)
+	flush := time.NewTicker(reportFlush)
+	refresh := time.NewTicker(targetsRefresh)
+	defer dispatch.Stop()
+	defer flush.Stop()
+	defer refresh.Stop()
+
+	log.Printf("[agent] started, reporting to %s", a.apiURL)
+	for {
+		select {
+		case <-dispatch.C:
+			a.dispatch(jobs)
+		case <-flush.C:
+			if err := a.flush(ctx); err != nil {
+				log.Printf("[agent] push reports: %v", err)
+			}
+		case <-refresh.C:
+			if err := a.refreshTargets(ctx); err != nil {
+				log.Printf("[agent] fetch targets: %v", err)
+			}
+		case <-ctx.Done():
+			close(jobs)
+			wg.Wait()
+			shutCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if err := a.flush(shutCtx); err != nil {
+				log.Printf("[agent] final push: %v", err)
+			}
+			cancel()
+			log.Println("[agent] stopped")
+			return
+		}
+	}
+}
+
+// dispatch hands every due target to the workers.
+func (a *Agent) dispatch(jobs chan<- Target) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	for id, t := range a.targets {
+		if now.Before(a.nextDue[id]) {
+			continue
+		}
+		select {
+		case jobs <- t:
+			a.nextDue[id] = now.Add(time.Duration(t.Interval) * time.Second)
+		default:
+			return // workers are busy; the rest stays due for the next tick
+		}
+	}
+}
+
+func (a *Agent) poll(ctx context.Context, t Target) {
+	r := Report{ServerID: t.ServerID, CheckedAt: time.Now()}
+	status, err := poller.QueryInfo(t.GameType, t.IP, t.Port, ctx.Done())
+	if ctx.Err() != nil {
+		return // interrupted by shutdown, not a real failure
+	}
+	if err != nil {
+		r.FailureReason = poller.ClassifyFailure(err)
+	} else {
+		r.OnlineStatus = true
+		r.PlayersNow = status.PlayersNow
+		r.PingMS = status.PingMS
+	}
+
+	a.mu.Lock()
+	a.pending = append(a.pending, r)
+	if over := len(a.pending) - maxPending; over > 0 {
+		a.pending = a.pending[over:]
+	}
+	a.mu.Unlock()
+}
+
+func (a *Agent) refreshTargets(ctx context.Context) error {
+	var targets []Target
+	if err := a.do(ctx, http.MethodGet, "/api/v1/agent/targets", nil, &targets); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	fresh := make(map[uint]Target, len(targets))
+	for _, t := range targets {
+		if t.Interval <= 0 {
+			t.Interval = 60
+		}
+		fresh[t.ServerID] = t
+	}
+	for id := range a.nextDue {
+		if _, ok := fresh[id]; !ok {
+			delete(a.nextDue, id)
+		}
+	}
+	a.targets = fresh
+	return nil
+}
+
+// flush pushes buffered reports; on failure they are kept for the next attempt.
+func (a *Agent) flush(ctx context.Context) error {
+	a.mu.Lock()
+	batch := a.pending
+	a.pending = nil
+	a.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := a.do(ctx, http.MethodPost, "/api/v1/agent/report", map[string]interface{}{"reports": batch}, nil)
+	if err != nil {
+		a.mu.Lock()
+		a.pending = append(batch, a.pending...)
+		if over := len(a.pending) - maxPending; over > 0 {
+			a.pending = a.pending[over:]
+		}
+		a.mu.Unlock()
+	}
+	return err
+}
+
+func (a *Agent) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, a.apiURL+path, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(TokenHeader, a.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: HTTP %d", method, path, resp.StatusCode)
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
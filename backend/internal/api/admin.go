@@ -110,6 +110,7 @@ func AdminDeleteUser(c echo.Context) error {
 	}
 
 	database.DB.Delete(&user)
+	database.DB.Where("user_id = ?", user.ID).Delete(&models.RCONAccess{})
 	{
 		aid, aname := actorFromCtx(c)
 		logAudit(aid, aname, "delete_user", "user", user.ID, user.Username)
@@ -150,6 +151,7 @@ func AdminBulkUsers(c echo.Context) error {
 		database.DB.Model(&models.User{}).Where("id IN ?", safeIDs).Update("banned", false)
 	case "delete":
 		database.DB.Where("id IN ?", safeIDs).Delete(&models.User{})
+		database.DB.Where("user_id IN ?", safeIDs).Delete(&models.RCONAccess{})
 	default:
 		return c.JSON(http.StatusBadRequest, echo.Map{"error": "unknown action"})
 	}
@@ -173,6 +175,9 @@ func AdminBulkServers(c echo.Context) error {
 	}
 
 	database.DB.Where("id IN ?", req.IDs).Delete(&models.Server{})
+	for _, id := range req.IDs {
+		invalidateServer(id)
+	}
 
 	aid, aname := actorFromCtx(c)
 	logAudit(aid, aname, "bulk_delete_servers", "server", 0, fmt.Sprintf("%v", req.IDs))
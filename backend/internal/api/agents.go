@@ -0,0 +1,207 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm/clause"
+
+	"github.com/RJ-Bond/js-monitoring/internal/agent"
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+	"github.com/RJ-Bond/js-monitoring/internal/poller"
+)
+
+// maxAgentReports — предел отчётов в одном запросе агента
+const maxAgentReports = 5000
+
+// AgentAuth проверяет токен агента в заголовке X-Agent-Token
+func AgentAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Request().Header.Get(agent.TokenHeader)
+		if token == "" {
+			return c.JSON(http.StatusUnauthorized, echo.Map{"error": "missing agent token"})
+		}
+		var a models.PollAgent
+		if err := database.DB.Where("token = ? AND enabled = ?", token, true).First(&a).Error; err != nil {
+			return c.JSON(http.StatusUnauthorized, echo.Map{"error": "invalid agent token"})
+		}
+		go database.DB.Model(&models.PollAgent{}).Where("id = ?", a.ID).
+			Updates(map[string]interface{}{"last_seen_at": time.Now(), "last_ip": c.RealIP()})
+		c.Set("agent", &a)
+		return next(c)
+	}
+}
+
+// AgentGetTargets GET /api/v1/agent/targets — серверы для опроса агентом
+func AgentGetTargets(c echo.Context) error {
+	var servers []models.Server
+	database.DB.Preload("Status").Find(&servers)
+
+	targets := make([]agent.Target, 0, len(servers))
+	for i := range servers {
+		s := &servers[i]
+		players := 0
+		if s.Status != nil {
+			players = s.Status.PlayersNow
+		}
+		targets = append(targets, agent.Target{
+			ServerID: s.ID,
+			GameType: s.GameType,
+			IP:       s.IP,
+			Port:     s.QueryAddrPort(),
+			Interval: int(poller.PollInterval(s, players).Seconds()),
+		})
+	}
+	return c.JSON(http.StatusOK, targets)
+}
+
+// AgentPostReport POST /api/v1/agent/report — результаты опроса от агента
+func AgentPostReport(c echo.Context) error {
+	a := c.Get("agent").(*models.PollAgent)
+	var req struct {
+		Reports []agent.Report `json:"reports"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	if len(req.Reports) > maxAgentReports {
+		return c.JSON(http.StatusRequestEntityTooLarge, echo.Map{"error": "too many reports"})
+	}
+
+	// В пачке может быть несколько опросов одного сервера — сохраняем последний
+	latest := make(map[uint]agent.Report, len(req.Reports))
+	now := time.Now()
+	for _, r := range req.Reports {
+		if r.CheckedAt.After(now) {
+			r.CheckedAt = now // часы агента спешат
+		}
+		if prev, ok := latest[r.ServerID]; !ok || r.CheckedAt.After(prev.CheckedAt) {
+			latest[r.ServerID] = r
+		}
+	}
+
+	rows := make([]models.RegionStatus, 0, len(latest))
+	for _, r := range latest {
+		rows = append(rows, models.RegionStatus{
+			ServerID:      r.ServerID,
+			AgentID:       a.ID,
+			Region:        a.Region,
+			OnlineStatus:  r.OnlineStatus,
+			PlayersNow:    r.PlayersNow,
+			PingMS:        r.PingMS,
+			FailureReason: r.FailureReason,
+			CheckedAt:     r.CheckedAt,
+		})
+	}
+	if len(rows) > 0 {
+		err := database.DB.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "server_id"}, {Name: "agent_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"region", "online_status", "players_now", "ping_ms", "failure_reason", "checked_at",
+			}),
+		}).CreateInBatches(rows, 500).Error
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+		}
+	}
+	return c.JSON(http.StatusOK, echo.Map{"accepted": len(rows)})
+}
+
+// GetServerRegions GET /api/v1/servers/:id/regions — статус сервера из регионов агентов
+func GetServerRegions(c echo.Context) error {
+	var rows []models.RegionStatus
+	database.DB.
+		Joins("JOIN poll_agents ON poll_agents.id = region_statuses.agent_id AND poll_agents.enabled = ?", true).
+		Where("region_statuses.server_id = ? AND region_statuses.checked_at > ?", c.Param("id"), time.Now().Add(-poller.AgentReportMaxAge)).
+		Order("region_statuses.region").
+		Find(&rows)
+	return c.JSON(http.StatusOK, rows)
+}
+
+// AdminGetAgents GET /api/v1/admin/agents
+func AdminGetAgents(c echo.Context) error {
+	var agents []models.PollAgent
+	database.DB.Order("region, name").Find(&agents)
+	return c.JSON(http.StatusOK, agents)
+}
+
+// AdminCreateAgent POST /api/v1/admin/agents — токен возвращается только в ответе
+func AdminCreateAgent(c echo.Context) error {
+	var req struct {
+		Name   string `json:"name"`
+		Region string `json:"region"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.Region = strings.TrimSpace(req.Region)
+	if req.Name == "" || req.Region == "" || len(req.Name) > 64 || len(req.Region) > 32 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "name (≤64) and region (≤32) are required"})
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "failed to generate token"})
+	}
+	token := hex.EncodeToString(b)
+
+	a := models.PollAgent{Name: req.Name, Region: req.Region, Token: token, Enabled: true}
+	if err := database.DB.Create(&a).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	aid, aname := actorFromCtx(c)
+	logAudit(aid, aname, "create_agent", "agent", a.ID, a.Name+" ("+a.Region+")")
+	return c.JSON(http.StatusCreated, echo.Map{"agent": a, "token": token})
+}
+
+// AdminUpdateAgent PUT /api/v1/admin/agents/:id
+func AdminUpdateAgent(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var a models.PollAgent
+	if err := database.DB.First(&a, id).Error; err != nil {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "agent not found"})
+	}
+	var req struct {
+		Name    string `json:"name"`
+		Region  string `json:"region"`
+		Enabled bool   `json:"enabled"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	updates := map[string]interface{}{"enabled": req.Enabled}
+	if name := strings.TrimSpace(req.Name); name != "" && len(name) <= 64 {
+		updates["name"] = name
+	}
+	if region := strings.TrimSpace(req.Region); region != "" && len(region) <= 32 {
+		updates["region"] = region
+	}
+	if err := database.DB.Model(&a).Updates(updates).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	aid, aname := actorFromCtx(c)
+	logAudit(aid, aname, "update_agent", "agent", a.ID, a.Name)
+	database.DB.First(&a, id)
+	return c.JSON(http.StatusOK, a)
+}
+
+// AdminDeleteAgent DELETE /api/v1/admin/agents/:id
+func AdminDeleteAgent(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var a models.PollAgent
+	if err := database.DB.First(&a, id).Error; err != nil {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "agent not found"})
+	}
+	database.DB.Where("agent_id = ?", a.ID).Delete(&models.RegionStatus{})
+	database.DB.Delete(&a)
+	aid, aname := actorFromCtx(c)
+	logAudit(aid, aname, "delete_agent", "agent", a.ID, a.Name)
+	return c.JSON(http.StatusOK, echo.Map{"message": "agent deleted"})
+}
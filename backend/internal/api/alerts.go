@@ -92,3 +92,126 @@ func UpdateAlertConfig(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, cfg)
 }
+
+// alertRuleConditions — допустимые условия правил; true — условие пороговое (нужен threshold)
+var alertRuleConditions = map[string]bool{
+	models.AlertRulePlayersAbove:   true,
+	models.AlertRulePlayersBelow:   true,
+	models.AlertRulePingAbove:      true,
+	models.AlertRuleServerFull:     false,
+	models.AlertRuleMapChanged:     false,
+	models.AlertRuleNameChanged:    false,
+	models.AlertRuleVersionChanged: false,
+}
+
+// alertRuleRequest — тело создания/изменения правила оповещения
+type alertRuleRequest struct {
+	Name        string `json:"name"`
+	Condition   string `json:"condition"`
+	Threshold   int    `json:"threshold"`
+	DurationMin int    `json:"duration_min"`
+	CooldownMin int    `json:"cooldown_min"`
+	Enabled     bool   `json:"enabled"`
+	TgChatID    string `json:"tg_chat_id"`
+	EmailTo     string `json:"email_to"`
+	WebhookURL  string `json:"webhook_url"`
+}
+
+// apply проверяет запрос и переносит его в правило; возвращает текст ошибки
+func (req *alertRuleRequest) apply(r *models.AlertRule) string {
+	threshold, ok := alertRuleConditions[req.Condition]
+	if !ok {
+		return "unknown condition: " + req.Condition
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.TgChatID = strings.TrimSpace(req.TgChatID)
+	req.EmailTo = strings.TrimSpace(req.EmailTo)
+	req.WebhookURL = strings.TrimSpace(req.WebhookURL)
+	switch {
+	case len(req.Name) > 100:
+		return "name is too long (max 100)"
+	case threshold && req.Threshold < 0:
+		return "threshold must not be negative"
+	case req.DurationMin < 0 || req.DurationMin > 1440:
+		return "duration_min must be between 0 and 1440"
+	case req.CooldownMin < 0 || req.CooldownMin > 10080:
+		return "cooldown_min must be between 0 and 10080"
+	case req.WebhookURL != "" && !strings.HasPrefix(req.WebhookURL, "https://"):
+		return "webhook_url must be an https URL"
+	}
+	if !threshold {
+		req.Threshold = 0
+	}
+	r.Name = req.Name
+	r.Condition = req.Condition
+	r.Threshold = req.Threshold
+	r.DurationMin = req.DurationMin
+	r.CooldownMin = req.CooldownMin
+	r.Enabled = req.Enabled
+	r.TgChatID = req.TgChatID
+	r.EmailTo = req.EmailTo
+	r.WebhookURL = req.WebhookURL
+	return ""
+}
+
+// GetAlertRules GET /api/v1/admin/alerts/:serverID/rules
+func GetAlertRules(c echo.Context) error {
+	rules := []models.AlertRule{}
+	database.DB.Where("server_id = ?", c.Param("serverID")).Order("id ASC").Find(&rules)
+	return c.JSON(http.StatusOK, rules)
+}
+
+// CreateAlertRule POST /api/v1/admin/alerts/:serverID/rules
+func CreateAlertRule(c echo.Context) error {
+	var server models.Server
+	if err := database.DB.First(&server, c.Param("serverID")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "server not found"})
+	}
+	var req alertRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	rule := models.AlertRule{ServerID: server.ID}
+	if msg := req.apply(&rule); msg != "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": msg})
+	}
+	// Create подставляет default вместо нулевых значений — Enabled=false и CooldownMin=0 сохраняем явно
+	if err := database.DB.Create(&rule).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	database.DB.Model(&rule).Select("enabled", "cooldown_min").Updates(&rule)
+	uid, uname := actorFromCtx(c)
+	logAudit(uid, uname, "create_alert_rule", "server", server.ID, rule.Condition)
+	return c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateAlertRule PUT /api/v1/admin/alerts/:serverID/rules/:ruleID
+func UpdateAlertRule(c echo.Context) error {
+	var rule models.AlertRule
+	if err := database.DB.Where("id = ? AND server_id = ?", c.Param("ruleID"), c.Param("serverID")).First(&rule).Error; err != nil {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "alert rule not found"})
+	}
+	var req alertRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	if msg := req.apply(&rule); msg != "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": msg})
+	}
+	database.DB.Save(&rule)
+	uid, uname := actorFromCtx(c)
+	logAudit(uid, uname, "update_alert_rule", "server", rule.ServerID, rule.Condition)
+	return c.JSON(http.StatusOK, rule)
+}
+
+// DeleteAlertRule DELETE /api/v1/admin/alerts/:serverID/rules/:ruleID
+func DeleteAlertRule(c echo.Context) error {
+	var rule models.AlertRule
+	if err := database.DB.Where("id = ? AND server_id = ?", c.Param("ruleID"), c.Param("serverID")).First(&rule).Error; err != nil {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "alert rule not found"})
+	}
+	database.DB.Delete(&rule)
+	uid, uname := actorFromCtx(c)
+	logAudit(uid, uname, "delete_alert_rule", "server", rule.ServerID, rule.Condition)
+	return c.JSON(http.StatusOK, echo.Map{"ok": true})
+}
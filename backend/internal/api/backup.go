@@ -57,31 +57,39 @@ type bkUser struct {
 }
 
 type bkServer struct {
-	ID          uint      `gorm:"column:id"           json:"id"`
-	UUID        string    `gorm:"column:uuid"         json:"uuid"`
-	Title       string    `gorm:"column:title"        json:"title"`
-	IP          string    `gorm:"column:ip"           json:"ip"`
-	DisplayIP   string    `gorm:"column:display_ip"   json:"display_ip"`
-	Port        uint16    `gorm:"column:port"         json:"port"`
-	GameType    string    `gorm:"column:game_type"    json:"game_type"`
-	SecretRCON  string    `gorm:"column:secret_rcon"  json:"secret_rcon"`
-	CountryCode string    `gorm:"column:country_code" json:"country_code"`
-	CountryName string    `gorm:"column:country_name" json:"country_name"`
-	OwnerID     uint      `gorm:"column:owner_id"     json:"owner_id"`
-	CreatedAt   time.Time `gorm:"column:created_at"   json:"created_at"`
-	UpdatedAt   time.Time `gorm:"column:updated_at"   json:"updated_at"`
+	ID                uint      `gorm:"column:id"                  json:"id"`
+	UUID              string    `gorm:"column:uuid"                json:"uuid"`
+	Title             string    `gorm:"column:title"               json:"title"`
+	IP                string    `gorm:"column:ip"                  json:"ip"`
+	DisplayIP         string    `gorm:"column:display_ip"          json:"display_ip"`
+	Port              uint16    `gorm:"column:port"                json:"port"`
+	GameType          string    `gorm:"column:game_type"           json:"game_type"`
+	SecretRCON        string    `gorm:"column:secret_rcon"         json:"secret_rcon"`
+	RCONPort          uint16    `gorm:"column:rcon_port"           json:"rcon_port"`
+	QueryPort         uint16    `gorm:"column:query_port"          json:"query_port"`
+	CountryCode       string    `gorm:"column:country_code"        json:"country_code"`
+	CountryName       string    `gorm:"column:country_name"        json:"country_name"`
+	OwnerID           uint      `gorm:"column:owner_id"            json:"owner_id"`
+	TrackedRules      string    `gorm:"column:tracked_rules"       json:"tracked_rules"`
+	PollInterval      int       `gorm:"column:poll_interval"       json:"poll_interval"`
+	EmptyPollInterval int       `gorm:"column:empty_poll_interval" json:"empty_poll_interval"`
+	CreatedAt         time.Time `gorm:"column:created_at"          json:"created_at"`
+	UpdatedAt         time.Time `gorm:"column:updated_at"          json:"updated_at"`
 }
 
 // BackupPayload is the top-level structure written to / read from a backup file.
 type BackupPayload struct {
-	Version        string                 `json:"version"`
-	ExportedAt     time.Time              `json:"exported_at"`
-	Settings       *bkSettings            `json:"settings,omitempty"`
-	Users          []bkUser               `json:"users"`
-	Servers        []bkServer             `json:"servers"`
-	AlertConfigs   []models.AlertsConfig  `json:"alert_configs"`
-	DiscordConfigs []models.DiscordConfig `json:"discord_configs"`
-	News           []models.NewsItem      `json:"news"`
+	Version        string                     `json:"version"`
+	ExportedAt     time.Time                  `json:"exported_at"`
+	Settings       *bkSettings                `json:"settings,omitempty"`
+	Users          []bkUser                   `json:"users"`
+	Servers        []bkServer                 `json:"servers"`
+	AlertConfigs   []models.AlertsConfig      `json:"alert_configs"`
+	AlertRules     []models.AlertRule         `json:"alert_rules,omitempty"`
+	DiscordConfigs []models.DiscordConfig     `json:"discord_configs"`
+	News           []models.NewsItem          `json:"news"`
+	Maintenance    []models.MaintenanceWindow `json:"maintenance_windows,omitempty"`
+	RCONAccesses   []models.RCONAccess        `json:"rcon_accesses,omitempty"`
 }
 
 // GetBackup GET /api/v1/admin/backup
@@ -122,11 +130,14 @@ func GetBackup(c echo.Context) error {
 	db.Raw("SELECT id, username, email, password_hash, steam_id, avatar, api_token, role, banned, totp_secret, totp_enabled, sessions_cleared_at, delete_scheduled_at, created_at, updated_at FROM users").Scan(&p.Users)
 
 	// Servers — raw scan to include secret_rcon
-	db.Raw("SELECT id, uuid, title, ip, display_ip, port, game_type, secret_rcon, country_code, country_name, owner_id, created_at, updated_at FROM servers").Scan(&p.Servers)
+	db.Raw("SELECT id, uuid, title, ip, display_ip, port, game_type, secret_rcon, rcon_port, query_port, country_code, country_name, owner_id, tracked_rules, poll_interval, empty_poll_interval, created_at, updated_at FROM servers").Scan(&p.Servers)
 
 	db.Find(&p.AlertConfigs)
+	db.Find(&p.AlertRules)
 	db.Find(&p.DiscordConfigs)
 	db.Find(&p.News)
+	db.Find(&p.Maintenance)
+	db.Find(&p.RCONAccesses)
 
 	filename := fmt.Sprintf("jsmon-backup-%s.json", time.Now().Format("2006-01-02"))
 	c.Response().Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
@@ -154,8 +165,16 @@ func RestoreBackup(c echo.Context) error {
 			"DELETE FROM discord_configs",
 			"DELETE FROM news_items",
 			"DELETE FROM server_statuses",
+			"DELETE FROM server_rules",
+			"DELETE FROM region_statuses",
+			"DELETE FROM server_favicons",
 			"DELETE FROM player_histories",
+			"DELETE FROM player_history_rollups",
 			"DELETE FROM player_sessions",
+			"DELETE FROM server_incidents",
+			"DELETE FROM maintenance_windows",
+			"DELETE FROM alert_rules",
+			"DELETE FROM rcon_accesses",
 			"DELETE FROM audit_logs",
 			"DELETE FROM servers",
 			"DELETE FROM users",
@@ -209,9 +228,10 @@ func RestoreBackup(c echo.Context) error {
 		// Servers
 		for _, s := range p.Servers {
 			err := tx.Exec(
-				`INSERT INTO servers (id,uuid,title,ip,display_ip,port,game_type,secret_rcon,country_code,country_name,owner_id,created_at,updated_at) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)`,
-				s.ID, s.UUID, s.Title, s.IP, s.DisplayIP, s.Port, s.GameType, s.SecretRCON,
-				s.CountryCode, s.CountryName, s.OwnerID, s.CreatedAt, s.UpdatedAt,
+				`INSERT INTO servers (id,uuid,title,ip,display_ip,port,game_type,secret_rcon,rcon_port,query_port,country_code,country_name,owner_id,tracked_rules,poll_interval,empty_poll_interval,created_at,updated_at) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+				s.ID, s.UUID, s.Title, s.IP, s.DisplayIP, s.Port, s.GameType, s.SecretRCON, s.RCONPort, s.QueryPort,
+				s.CountryCode, s.CountryName, s.OwnerID, s.TrackedRules, s.PollInterval, s.EmptyPollInterval,
+				s.CreatedAt, s.UpdatedAt,
 			).Error
 			if err != nil {
 				return fmt.Errorf("server %d: %w", s.ID, err)
@@ -225,6 +245,28 @@ func RestoreBackup(c echo.Context) error {
 			}
 		}
 
+		// AlertRules
+		for i := range p.AlertRules {
+			if err := tx.Create(&p.AlertRules[i]).Error; err != nil {
+				return fmt.Errorf("alert rule %d: %w", p.AlertRules[i].ID, err)
+			}
+			tx.Model(&p.AlertRules[i]).Select("enabled", "cooldown_min").Updates(&p.AlertRules[i])
+		}
+
+		// RCONAccesses
+		for i := range p.RCONAccesses {
+			if err := tx.Create(&p.RCONAccesses[i]).Error; err != nil {
+				return fmt.Errorf("rcon access %d: %w", p.RCONAccesses[i].ID, err)
+			}
+		}
+
+		// MaintenanceWindows
+		for i := range p.Maintenance {
+			if err := tx.Create(&p.Maintenance[i]).Error; err != nil {
+				return fmt.Errorf("maintenance window %d: %w", p.Maintenance[i].ID, err)
+			}
+		}
+
 		// DiscordConfigs
 		for i := range p.DiscordConfigs {
 			if err := tx.Create(&p.DiscordConfigs[i]).Error; err != nil {
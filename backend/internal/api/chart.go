@@ -11,7 +11,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 
-	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/history"
 	"github.com/RJ-Bond/js-monitoring/internal/models"
 )
 
@@ -35,15 +35,10 @@ func GetServerChart(c echo.Context) error {
 		period = "24h"
 	}
 
-	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	window := time.Duration(hours) * time.Hour
+	points := history.Points(uint(serverID), time.Now().Add(-window), history.Resolution(window))
 
-	var history []models.PlayerHistory
-	database.DB.
-		Where("server_id = ? AND timestamp > ?", serverID, since).
-		Order("timestamp ASC").
-		Find(&history)
-
-	png, err := renderChart(history, period)
+	png, err := renderChart(points, period)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "chart generation failed"})
 	}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/labstack/echo/v4"
 
@@ -62,6 +63,12 @@ var gameThumbnailURL = map[string]string{
 	"arma3":    "https://cdn.cloudflare.steamstatic.com/steam/apps/107410/capsule_sm_120.jpg",
 }
 
+// Лимиты embed: Discord отклоняет сообщение целиком, если правил слишком много
+const (
+	discordMaxRuleFields = 10 // как в embed бота
+	discordMaxEmbedChars = 6000
+)
+
 // BuildDiscordPayload строит JSON-тело embed-сообщения для Discord webhook (статус сервера).
 func BuildDiscordPayload(siteName, appURL string, srv *models.Server, status *models.ServerStatus) []byte {
 	color := 10038562 // красный (офлайн)
@@ -104,6 +111,23 @@ func BuildDiscordPayload(siteName, appURL string, srv *models.Server, status *mo
 				Name: "Карта", Value: status.CurrentMap, Inline: true,
 			})
 		}
+		size := utf8.RuneCountInString(title) + utf8.RuneCountInString(desc) + utf8.RuneCountInString(siteName)
+		for _, f := range fields {
+			size += utf8.RuneCountInString(f.Name) + utf8.RuneCountInString(f.Value)
+		}
+		added := 0
+		for _, r := range srv.Rules {
+			if r.Value == "" {
+				continue
+			}
+			n := utf8.RuneCountInString(r.Key) + utf8.RuneCountInString(r.Value)
+			if added == discordMaxRuleFields || size+n > discordMaxEmbedChars {
+				break
+			}
+			fields = append(fields, discordField{Name: r.Key, Value: r.Value, Inline: true})
+			size += n
+			added++
+		}
 	}
 
 	embed := discordEmbed{
@@ -322,7 +346,7 @@ func SendDiscordTest(c echo.Context) error {
 	}
 
 	var srv models.Server
-	if err := database.DB.Preload("Status").First(&srv, serverID).Error; err != nil {
+	if err := database.DB.Preload("Status").Preload("Rules").First(&srv, serverID).Error; err != nil {
 		return c.JSON(http.StatusNotFound, echo.Map{"error": "server not found"})
 	}
 
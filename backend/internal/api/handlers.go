@@ -1,20 +1,26 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/history"
+	"github.com/RJ-Bond/js-monitoring/internal/maintenance"
 	"github.com/RJ-Bond/js-monitoring/internal/models"
 	"github.com/RJ-Bond/js-monitoring/internal/poller"
+	"github.com/RJ-Bond/js-monitoring/internal/sla"
 )
 
 // Shared HTTP client with connection pooling (reused across all handler requests)
@@ -27,6 +33,20 @@ var sharedHTTPClient = &http.Client{
 	},
 }
 
+// activePoller — опросчик этой реплики; API сообщает ему о создании, изменении и удалении серверов.
+// nil, пока реплика не лидер: тогда лидер подхватит изменения по servers.updated_at (см. schedulerWatch).
+var activePoller atomic.Pointer[poller.Poller]
+
+// SetPoller регистрирует запущенный опросчик (nil — опросчик остановлен)
+func SetPoller(p *poller.Poller) { activePoller.Store(p) }
+
+// invalidateServer перепланирует опрос сервера после изменения через API
+func invalidateServer(id uint) {
+	if p := activePoller.Load(); p != nil {
+		p.Invalidate(id)
+	}
+}
+
 // In-memory GeoIP cache (24h TTL) to avoid repeated ip-api.com calls for the same IP
 type geoIPEntry struct {
 	country, code string
@@ -41,6 +61,10 @@ func GetServers(c echo.Context) error {
 	if err := database.DB.Preload("Status").Preload("AlertConfig").Find(&servers).Error; err != nil {
 		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
 	}
+	active := maintenance.ActiveServers(time.Now())
+	for i := range servers {
+		servers[i].InMaintenance = active[servers[i].ID]
+	}
 	return c.JSON(http.StatusOK, servers)
 }
 
@@ -48,18 +72,49 @@ func GetServers(c echo.Context) error {
 func GetServer(c echo.Context) error {
 	id := c.Param("id")
 	var server models.Server
-	if err := database.DB.Preload("Status").Preload("AlertConfig").First(&server, id).Error; err != nil {
+	if err := database.DB.Preload("Status").Preload("AlertConfig").Preload("Rules").First(&server, id).Error; err != nil {
 		return c.JSON(http.StatusNotFound, echo.Map{"error": "server not found"})
 	}
+	server.InMaintenance = maintenance.Active(server.ID, time.Now())
 	return c.JSON(http.StatusOK, server)
 }
 
+// GetServerFavicon GET /api/v1/servers/:id/favicon — public
+// Отдаёт иконку сервера из ответа опроса (Minecraft favicon). Используется в веб-интерфейсе и Discord embed.
+func GetServerFavicon(c echo.Context) error {
+	var fav models.ServerFavicon
+	if database.DB.Where("server_id = ?", c.Param("id")).First(&fav).Error != nil || fav.Data == "" {
+		return c.NoContent(http.StatusNotFound)
+	}
+	// Отдаётся только PNG: тип из ответа сервера игры не должен попасть в Content-Type
+	if !poller.IsPNGDataURL(fav.Data) {
+		return c.NoContent(http.StatusNotFound)
+	}
+	_, data, ok := decodeDataURL(fav.Data)
+	if !ok {
+		return c.NoContent(http.StatusNotFound)
+	}
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	c.Response().Header().Set("ETag", `"`+fav.Hash+`"`)
+	c.Response().Header().Set("X-Content-Type-Options", "nosniff")
+	return c.Blob(http.StatusOK, "image/png", data)
+}
+
 // CreateServer POST /api/v1/servers
 func CreateServer(c echo.Context) error {
-	var server models.Server
-	if err := c.Bind(&server); err != nil {
+	// SecretRCON скрыт из JSON (json:"-"), поэтому принимаем его отдельным полем
+	var req struct {
+		models.Server
+		SecretRCON string `json:"secret_rcon_key"`
+	}
+	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
 	}
+	if msg := validatePollIntervals(req.PollInterval, req.EmptyPollInterval); msg != "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": msg})
+	}
+	server := req.Server
+	server.SecretRCON = req.SecretRCON
 	// Устанавливаем владельца из JWT
 	if uid, ok := c.Get("user_id").(float64); ok {
 		server.OwnerID = uint(uid)
@@ -69,6 +124,7 @@ func CreateServer(c echo.Context) error {
 	}
 	actorID, actorName := actorFromCtx(c)
 	logAudit(actorID, actorName, "create_server", "server", server.ID, server.Title)
+	invalidateServer(server.ID)
 	// Определяем страну по IP в фоне
 	go func(s models.Server) {
 		country, code := fetchGeoIP(s.IP)
@@ -80,6 +136,37 @@ func CreateServer(c echo.Context) error {
 	return c.JSON(http.StatusCreated, server)
 }
 
+// Допустимые переопределения интервала опроса, секунды (0 — интервал по умолчанию)
+const (
+	minPollInterval = 5
+	maxPollInterval = 3600
+)
+
+// validatePollIntervals возвращает текст ошибки для недопустимого интервала опроса
+func validatePollIntervals(intervals ...int) string {
+	for _, v := range intervals {
+		if v != 0 && (v < minPollInterval || v > maxPollInterval) {
+			return fmt.Sprintf("poll interval must be 0 (default) or between %d and %d seconds", minPollInterval, maxPollInterval)
+		}
+	}
+	return ""
+}
+
+// managedServer загружает сервер :id и проверяет, что текущий пользователь — владелец или админ.
+// При отказе возвращает nil и уже записанный ответ с ошибкой.
+func managedServer(c echo.Context) (*models.Server, error) {
+	var server models.Server
+	if err := database.DB.First(&server, c.Param("id")).Error; err != nil {
+		return nil, c.JSON(http.StatusNotFound, echo.Map{"error": "server not found"})
+	}
+	role, _ := c.Get("role").(string)
+	uid, _ := c.Get("user_id").(float64)
+	if role != "admin" && server.OwnerID != uint(uid) {
+		return nil, c.JSON(http.StatusForbidden, echo.Map{"error": "not your server"})
+	}
+	return &server, nil
+}
+
 // UpdateServer PUT /api/v1/servers/:id
 func UpdateServer(c echo.Context) error {
 	id := c.Param("id")
@@ -97,29 +184,45 @@ func UpdateServer(c echo.Context) error {
 
 	// Use a dedicated payload struct so json:"-" on Server fields doesn't block binding
 	var payload struct {
-		Title        string `json:"title"`
-		IP           string `json:"ip"`
-		DisplayIP    string `json:"display_ip"`
-		Port         uint16 `json:"port"`
-		GameType     string `json:"game_type"`
-		SecretRCON   string `json:"secret_rcon_key"`
-		DiscordColor string `json:"discord_color"`
+		Title             string  `json:"title"`
+		IP                string  `json:"ip"`
+		DisplayIP         string  `json:"display_ip"`
+		Port              uint16  `json:"port"`
+		GameType          string  `json:"game_type"`
+		SecretRCON        string  `json:"secret_rcon_key"`
+		RCONPort          *uint16 `json:"rcon_port"` // nil — поле не передано, порт не меняем
+		DiscordColor      string  `json:"discord_color"`
+		TrackedRules      string  `json:"tracked_rules"`
+		QueryPort         uint16  `json:"query_port"`
+		PollInterval      int     `json:"poll_interval"`
+		EmptyPollInterval int     `json:"empty_poll_interval"`
 	}
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
 	}
+	if msg := validatePollIntervals(payload.PollInterval, payload.EmptyPollInterval); msg != "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": msg})
+	}
 
 	updates := map[string]interface{}{
-		"title":         payload.Title,
-		"ip":            payload.IP,
-		"display_ip":    payload.DisplayIP,
-		"port":          payload.Port,
-		"game_type":     payload.GameType,
-		"discord_color": payload.DiscordColor,
+		"title":               payload.Title,
+		"ip":                  payload.IP,
+		"display_ip":          payload.DisplayIP,
+		"port":                payload.Port,
+		"game_type":           payload.GameType,
+		"discord_color":       payload.DiscordColor,
+		"tracked_rules":       payload.TrackedRules,
+		"query_port":          payload.QueryPort,
+		"poll_interval":       payload.PollInterval,
+		"empty_poll_interval": payload.EmptyPollInterval,
 	}
 	if payload.SecretRCON != "" {
 		updates["secret_rcon"] = payload.SecretRCON
 	}
+	if payload.RCONPort != nil {
+		updates["rcon_port"] = *payload.RCONPort
+	}
+	oldTrackedRules := server.TrackedRules
 	if err := database.DB.Model(&server).Updates(updates).Error; err != nil {
 		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
 	}
@@ -127,6 +230,16 @@ func UpdateServer(c echo.Context) error {
 		aid, aname := actorFromCtx(c)
 		logAudit(aid, aname, "update_server", "server", server.ID, server.Title)
 	}
+	// Правила, которые больше не отслеживаются, поллер не запрашивает и сам не удалит
+	if payload.TrackedRules != oldTrackedRules {
+		tracked := models.Server{TrackedRules: payload.TrackedRules}
+		q := database.DB.Where("server_id = ?", server.ID)
+		if keys := tracked.TrackedRuleKeys(); len(keys) > 0 {
+			q = q.Where("`key` NOT IN ?", keys)
+		}
+		q.Delete(&models.ServerRule{})
+	}
+	invalidateServer(server.ID)
 
 	// Обновляем страну если IP изменился
 	if payload.IP != "" && payload.IP != server.IP {
@@ -139,7 +252,7 @@ func UpdateServer(c echo.Context) error {
 		}(payload.IP, server.ID)
 	}
 
-	database.DB.Preload("Status").Preload("AlertConfig").First(&server, id)
+	database.DB.Preload("Status").Preload("AlertConfig").Preload("Rules").First(&server, id)
 	return c.JSON(http.StatusOK, server)
 }
 
@@ -159,10 +272,18 @@ func DeleteServer(c echo.Context) error {
 	}
 
 	database.DB.Delete(&models.Server{}, id)
+	database.DB.Where("server_id = ?", server.ID).Delete(&models.ServerRule{})
+	database.DB.Where("server_id = ?", server.ID).Delete(&models.ServerFavicon{})
+	database.DB.Where("server_id = ?", server.ID).Delete(&models.RegionStatus{})
+	database.DB.Where("server_id = ?", server.ID).Delete(&models.ServerIncident{})
+	database.DB.Where("server_id = ?", server.ID).Delete(&models.MaintenanceWindow{})
+	database.DB.Where("server_id = ?", server.ID).Delete(&models.AlertRule{})
+	database.DB.Where("server_id = ?", server.ID).Delete(&models.RCONAccess{})
 	{
 		aid, aname := actorFromCtx(c)
 		logAudit(aid, aname, "delete_server", "server", server.ID, server.Title)
 	}
+	invalidateServer(server.ID)
 	return c.JSON(http.StatusOK, echo.Map{"message": "server deleted"})
 }
 
@@ -186,15 +307,11 @@ func GetServerHistory(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid server id"})
 	}
 
-	since := time.Now().Add(-time.Duration(hours) * time.Hour)
-
-	var history []models.PlayerHistory
-	database.DB.
-		Where("server_id = ? AND timestamp > ?", serverID, since).
-		Order("timestamp ASC").
-		Find(&history)
+	// Длинные периоды читаются из агрегатов, а не из сырых точек
+	window := time.Duration(hours) * time.Hour
+	points := history.Points(uint(serverID), time.Now().Add(-window), history.Resolution(window))
 
-	return c.JSON(http.StatusOK, history)
+	return c.JSON(http.StatusOK, points)
 }
 
 // GetStats GET /api/v1/stats — агрегированная статистика
@@ -224,24 +341,74 @@ func GetServerPlayers(c echo.Context) error {
 
 	var players []models.ServerPlayer
 	var err error
-
-	switch server.GameType {
-	case "source", "fivem", "gmod", "valheim", "dayz", "squad", "vrising", "terraria":
-		players, err = poller.QuerySourcePlayers(server.IP, server.Port)
-	case "samp":
-		players, err = poller.QuerySAMPPlayers(server.IP, server.Port)
-	case "minecraft", "minecraft_bedrock":
-		players, err = poller.QueryMinecraftPlayers(server.IP, server.Port)
-	default:
-		players = []models.ServerPlayer{}
+	if proto := poller.ProtocolFor(server.GameType); proto.Capabilities().Players {
+		players, err = proto.Players(server.IP, server.QueryAddrPort())
 	}
 
 	if err != nil || players == nil {
 		players = []models.ServerPlayer{}
 	}
+	// Идентификаторы игроков (license, steam, discord) видят только владелец и админ
+	role, _ := c.Get("role").(string)
+	uid, _ := c.Get("user_id").(float64)
+	if role != "admin" && (uid == 0 || server.OwnerID != uint(uid)) {
+		for i := range players {
+			players[i].Identifiers = nil
+		}
+	}
 	return c.JSON(http.StatusOK, players)
 }
 
+// ProbeServer POST /api/v1/servers/probe — опрашивает ip:port всеми протоколами,
+// чтобы форма добавления могла подставить game_type и проверить доступность
+func ProbeServer(c echo.Context) error {
+	var req struct {
+		IP   string `json:"ip"`
+		Port uint16 `json:"port"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	req.IP = strings.TrimSpace(req.IP)
+	if req.IP == "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "ip required"})
+	}
+	addr, err := probeTarget(c.Request().Context(), req.IP)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, poller.Probe(addr, req.Port))
+}
+
+// probeTarget разрешает адрес до опроса и отклоняет loopback, частные, link-local и
+// multicast сети — иначе probe сканирует порты внутри сети мониторинга. Опрашивается
+// уже разрешённый IP, чтобы повторное разрешение имени не подменило проверенный адрес.
+func probeTarget(ctx context.Context, host string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+	if err != nil || len(ips) == 0 {
+		return "", fmt.Errorf("cannot resolve %s", host)
+	}
+	target := ips[0]
+	for _, ip := range ips {
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+			ip.IsMulticast() || ip.IsUnspecified() {
+			return "", fmt.Errorf("address %s is not allowed", ip)
+		}
+		// Часть протоколов (SA-MP) работает только по IPv4
+		if ip.To4() != nil && target.To4() == nil {
+			target = ip
+		}
+	}
+	return target.String(), nil
+}
+
+// GetGames GET /api/v1/games — поддерживаемые game_type, их протоколы и возможности
+func GetGames(c echo.Context) error {
+	return c.JSON(http.StatusOK, poller.Games())
+}
+
 // GetLeaderboard GET /api/v1/servers/:id/leaderboard?period=7d|30d|all
 func GetLeaderboard(c echo.Context) error {
 	serverID, err := strconv.Atoi(c.Param("id"))
@@ -574,28 +741,32 @@ func fetchGeoIP(ip string) (country, code string) {
 
 // ─── Uptime ───────────────────────────────────────────────────────────────────
 
-// GetUptime GET /api/v1/servers/:id/uptime — процент аптайма за 24 часа
+// GetUptime GET /api/v1/servers/:id/uptime?period=24h|7d|30d|month | ?month=YYYY-MM | ?from=&to=
+// Аптайм, взвешенный по времени, с разбивкой по суткам. Окна плановых работ не учитываются,
+// ?exclude=from/to,... исключает ещё интервалы. uptime_24h оставлен для совместимости и равен uptime при периоде 24h.
 func GetUptime(c echo.Context) error {
-	id := c.Param("id")
-	since := time.Now().Add(-24 * time.Hour)
-
-	var total, online int64
-	database.DB.Model(&models.PlayerHistory{}).
-		Where("server_id = ? AND timestamp > ?", id, since).
-		Count(&total)
-	database.DB.Model(&models.PlayerHistory{}).
-		Where("server_id = ? AND is_online = ? AND timestamp > ?", id, true, since).
-		Count(&online)
+	var server models.Server
+	if err := database.DB.First(&server, c.Param("id")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "server not found"})
+	}
+	period, from, to, msg := uptimeWindow(c)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": msg})
+	}
+	exclude, msg := uptimeExclusions(c)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": msg})
+	}
 
-	pct := 0.0
-	if total > 0 {
-		pct = float64(online) / float64(total) * 100
+	resp := struct {
+		Period string `json:"period"`
+		sla.Report
+		Uptime24h *float64 `json:"uptime_24h,omitempty"`
+	}{Period: period, Report: sla.Compute(&server, from, to, exclude)}
+	if period == "24h" {
+		resp.Uptime24h = resp.Uptime
 	}
-	return c.JSON(http.StatusOK, echo.Map{
-		"uptime_24h": pct,
-		"total":      total,
-		"online":     online,
-	})
+	return c.JSON(http.StatusOK, resp)
 }
 
 // ─── Global Leaderboard ───────────────────────────────────────────────────────
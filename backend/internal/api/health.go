@@ -8,6 +8,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/poller"
 )
 
 var appStartTime = time.Now()
@@ -36,6 +37,12 @@ func GetSystemHealth(c echo.Context) error {
 
 	uptimeSec := int64(time.Since(appStartTime).Seconds())
 
+	var pollerStats *poller.Stats
+	if p := activePoller.Load(); p != nil {
+		s := p.Stats()
+		pollerStats = &s
+	}
+
 	return c.JSON(http.StatusOK, echo.Map{
 		"uptime_seconds":    uptimeSec,
 		"goroutines":        runtime.NumGoroutine(),
@@ -47,5 +54,6 @@ func GetSystemHealth(c echo.Context) error {
 		"db_in_use":         dbStats.InUse,
 		"db_idle":           dbStats.Idle,
 		"go_version":        runtime.Version(),
+		"poller":            pollerStats,
 	})
 }
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// incidentsPageSize — инцидентов на странице списка
+const incidentsPageSize = 50
+
+// incidentRow — инцидент со служебными полями для списков
+type incidentRow struct {
+	models.ServerIncident
+	ServerTitle string `json:"server_title"`
+}
+
+// incidentStats — сводка по инцидентам за период
+type incidentStats struct {
+	Period          string `json:"period"`
+	Incidents       int    `json:"incidents"`        // начались за период
+	Open            int    `json:"open"`             // не закрыты сейчас
+	DowntimeSeconds int64  `json:"downtime_seconds"` // суммарный простой внутри периода
+	MTTRSeconds     *int64 `json:"mttr_seconds"`     // среднее время восстановления; nil — закрытых инцидентов не было
+	MTBFSeconds     *int64 `json:"mtbf_seconds"`     // среднее время работы между отказами; nil — отказов не было
+}
+
+// incidentPeriod разбирает ?period=7d|30d|90d (по умолчанию 30d)
+func incidentPeriod(c echo.Context) (string, time.Duration) {
+	switch p := c.QueryParam("period"); p {
+	case "7d":
+		return p, 7 * 24 * time.Hour
+	case "90d":
+		return p, 90 * 24 * time.Hour
+	default:
+		return "30d", 30 * 24 * time.Hour
+	}
+}
+
+// computeIncidentStats считает MTTR/MTBF по инцидентам серверов servers за окно window.
+// Время наблюдения сервера начинается не раньше его добавления в мониторинг.
+func computeIncidentStats(servers []models.Server, period string, window time.Duration) incidentStats {
+	st := incidentStats{Period: period}
+	if len(servers) == 0 {
+		return st
+	}
+	now := time.Now()
+	since := now.Add(-window)
+
+	ids := make([]uint, len(servers))
+	var observed time.Duration
+	for i, s := range servers {
+		ids[i] = s.ID
+		from := since
+		if s.CreatedAt.After(from) {
+			from = s.CreatedAt
+		}
+		observed += now.Sub(from)
+	}
+
+	var rows []models.ServerIncident
+	database.DB.
+		Where("server_id IN ? AND started_at < ? AND (resolved_at IS NULL OR resolved_at > ?)", ids, now, since).
+		Find(&rows)
+
+	var downtime time.Duration
+	var resolved, repairSec int64
+	for _, r := range rows {
+		start, end := r.StartedAt, now
+		if start.Before(since) {
+			start = since
+		}
+		if r.ResolvedAt != nil {
+			end = *r.ResolvedAt
+			resolved++
+			repairSec += int64(r.DurationSec)
+		} else {
+			st.Open++
+		}
+		if end.After(start) {
+			downtime += end.Sub(start)
+		}
+		if !r.StartedAt.Before(since) {
+			st.Incidents++
+		}
+	}
+
+	st.DowntimeSeconds = int64(downtime.Seconds())
+	if resolved > 0 {
+		mttr := repairSec / resolved
+		st.MTTRSeconds = &mttr
+	}
+	if st.Incidents > 0 {
+		mtbf := int64((observed - downtime).Seconds()) / int64(st.Incidents)
+		st.MTBFSeconds = &mtbf
+	}
+	return st
+}
+
+// listIncidents отдаёт страницу инцидентов из q (уже отфильтрованного) с названиями серверов
+func listIncidents(c echo.Context, q *gorm.DB) ([]incidentRow, int64) {
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+	if c.QueryParam("open") == "true" {
+		q = q.Where("server_incidents.resolved_at IS NULL")
+	}
+
+	var total int64
+	q.Count(&total)
+
+	rows := []incidentRow{}
+	q.Select("server_incidents.*, servers.title AS server_title").
+		Joins("LEFT JOIN servers ON servers.id = server_incidents.server_id").
+		Order("server_incidents.started_at DESC").
+		Limit(incidentsPageSize).Offset((page - 1) * incidentsPageSize).
+		Scan(&rows)
+	return rows, total
+}
+
+// GetServerIncidents GET /api/v1/servers/:id/incidents?period=7d|30d|90d&page=N&open=true
+func GetServerIncidents(c echo.Context) error {
+	var server models.Server
+	if err := database.DB.First(&server, c.Param("id")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "server not found"})
+	}
+	period, window := incidentPeriod(c)
+
+	items, total := listIncidents(c, database.DB.Model(&models.ServerIncident{}).
+		Where("server_incidents.server_id = ?", server.ID))
+	return c.JSON(http.StatusOK, echo.Map{
+		"items": items,
+		"total": total,
+		"stats": computeIncidentStats([]models.Server{server}, period, window),
+	})
+}
+
+// AdminGetIncidents GET /api/v1/admin/incidents?period=7d|30d|90d&page=N&open=true — по всем серверам
+func AdminGetIncidents(c echo.Context) error {
+	period, window := incidentPeriod(c)
+	var servers []models.Server
+	database.DB.Select("id", "created_at").Find(&servers)
+
+	items, total := listIncidents(c, database.DB.Model(&models.ServerIncident{}))
+	return c.JSON(http.StatusOK, echo.Map{
+		"items": items,
+		"total": total,
+		"stats": computeIncidentStats(servers, period, window),
+	})
+}
+
+// AckIncident POST /api/v1/servers/:id/incidents/:incidentID/ack — отметка, что простой разобран
+func AckIncident(c echo.Context) error {
+	var server models.Server
+	if err := database.DB.First(&server, c.Param("id")).Error; err != nil {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "server not found"})
+	}
+	role, _ := c.Get("role").(string)
+	uid, uname := actorFromCtx(c)
+	if role != "admin" && server.OwnerID != uid {
+		return c.JSON(http.StatusForbidden, echo.Map{"error": "not your server"})
+	}
+
+	var inc models.ServerIncident
+	if err := database.DB.Where("id = ? AND server_id = ?", c.Param("incidentID"), server.ID).First(&inc).Error; err != nil {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "incident not found"})
+	}
+	var req struct {
+		Note string `json:"note"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	note := strings.TrimSpace(req.Note)
+	if len(note) > 500 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "note is too long (max 500)"})
+	}
+
+	now := time.Now()
+	database.DB.Model(&inc).Updates(map[string]interface{}{
+		"ack_user_id":  uid,
+		"ack_username": uname,
+		"ack_at":       now,
+		"ack_note":     note,
+	})
+	logAudit(uid, uname, "ack_incident", "server", server.ID, "incident #"+strconv.Itoa(int(inc.ID)))
+
+	database.DB.First(&inc, inc.ID)
+	return c.JSON(http.StatusOK, inc)
+}
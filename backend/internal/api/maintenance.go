@@ -0,0 +1,138 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/maintenance"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// maxMaintenanceWindows — окон плановых работ на один сервер
+const maxMaintenanceWindows = 20
+
+// maintenancePastGrace — допуск для starts_at в прошлом: форма выбирает время с точностью до минуты
+const maintenancePastGrace = time.Minute
+
+// maintenanceRow — окно работ с текущим и ближайшим вхождением
+type maintenanceRow struct {
+	models.MaintenanceWindow
+	Active bool                    `json:"active"`
+	Next   *maintenance.Occurrence `json:"next"` // nil — разовое окно уже прошло
+}
+
+// GetMaintenanceWindows GET /api/v1/servers/:id/maintenance — окна плановых работ сервера
+func GetMaintenanceWindows(c echo.Context) error {
+	var windows []models.MaintenanceWindow
+	database.DB.Where("server_id = ?", c.Param("id")).Order("starts_at ASC").Find(&windows)
+
+	now := time.Now()
+	rows := make([]maintenanceRow, 0, len(windows))
+	for i := range windows {
+		row := maintenanceRow{MaintenanceWindow: windows[i]}
+		if next, ok := maintenance.Next(&windows[i], now); ok {
+			row.Next = &next
+			row.Active = !next.From.After(now)
+		}
+		rows = append(rows, row)
+	}
+	return c.JSON(http.StatusOK, rows)
+}
+
+// CreateMaintenanceWindow POST /api/v1/servers/:id/maintenance — владелец или админ
+// Тело: {"title", "starts_at" (RFC3339), "duration_min", "recurrence": ""|"daily"|"weekly"}
+func CreateMaintenanceWindow(c echo.Context) error {
+	server, errResp := managedServer(c)
+	if server == nil {
+		return errResp
+	}
+	var req struct {
+		Title       string    `json:"title"`
+		StartsAt    time.Time `json:"starts_at"`
+		DurationMin int       `json:"duration_min"`
+		Recurrence  string    `json:"recurrence"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	if len(req.Title) > 200 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "title is too long (max 200)"})
+	}
+	if req.StartsAt.IsZero() {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "starts_at is required"})
+	}
+	// Повторяющееся окно не может быть длиннее периода повторения
+	var maxDuration int
+	switch req.Recurrence {
+	case models.MaintenanceOnce, models.MaintenanceWeekly:
+		maxDuration = 7 * 24 * 60
+	case models.MaintenanceDaily:
+		maxDuration = 24 * 60
+	default:
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "recurrence must be empty, daily or weekly"})
+	}
+	if req.DurationMin < 1 || req.DurationMin > maxDuration {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": fmt.Sprintf("duration_min must be between 1 and %d", maxDuration)})
+	}
+	// Окно в прошлом задним числом исключило бы простой из SLA
+	earliest := time.Now().Add(-maintenancePastGrace)
+	if req.Recurrence == models.MaintenanceOnce {
+		if req.StartsAt.Add(time.Duration(req.DurationMin) * time.Minute).Before(earliest) {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "maintenance window has already ended"})
+		}
+	} else if req.StartsAt.Before(earliest) {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "starts_at of a recurring window must not be in the past"})
+	}
+
+	var count int64
+	database.DB.Model(&models.MaintenanceWindow{}).Where("server_id = ?", server.ID).Count(&count)
+	if count >= maxMaintenanceWindows {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": fmt.Sprintf("too many maintenance windows (max %d)", maxMaintenanceWindows)})
+	}
+
+	uid, uname := actorFromCtx(c)
+	w := models.MaintenanceWindow{
+		ServerID:    server.ID,
+		Title:       req.Title,
+		StartsAt:    req.StartsAt,
+		DurationMin: req.DurationMin,
+		Recurrence:  req.Recurrence,
+		CreatedBy:   uid,
+	}
+	if err := database.DB.Create(&w).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	logAudit(uid, uname, "create_maintenance", "server", server.ID,
+		fmt.Sprintf("%s, %d min, %s", w.StartsAt.Format(time.RFC3339), w.DurationMin, w.Recurrence))
+	return c.JSON(http.StatusCreated, w)
+}
+
+// DeleteMaintenanceWindow DELETE /api/v1/servers/:id/maintenance/:windowID — владелец или админ
+func DeleteMaintenanceWindow(c echo.Context) error {
+	server, errResp := managedServer(c)
+	if server == nil {
+		return errResp
+	}
+	var w models.MaintenanceWindow
+	if err := database.DB.Where("id = ? AND server_id = ?", c.Param("windowID"), server.ID).First(&w).Error; err != nil {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "maintenance window not found"})
+	}
+	if err := database.DB.Delete(&w).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+
+	// Прошедшие вхождения исключались из SLA — после удаления отчёт за них изменится
+	details := fmt.Sprintf("window #%d", w.ID)
+	if past := maintenance.Occurrences(&w, w.StartsAt, time.Now()); len(past) > 0 {
+		details += fmt.Sprintf(", changes past SLA: %d occurrence(s) since %s", len(past), w.StartsAt.Format(time.RFC3339))
+	}
+	uid, uname := actorFromCtx(c)
+	logAudit(uid, uname, "delete_maintenance", "server", server.ID, details)
+	return c.JSON(http.StatusOK, echo.Map{"ok": true})
+}
@@ -22,43 +22,9 @@ func JWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 			return apiKeyAuth(c, key, next)
 		}
 
-		auth := c.Request().Header.Get("Authorization")
-		if !strings.HasPrefix(auth, "Bearer ") {
-			return c.JSON(http.StatusUnauthorized, echo.Map{"error": "missing or invalid authorization header"})
-		}
-		tokenStr := strings.TrimPrefix(auth, "Bearer ")
-
-		token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-			}
-			return jwtSecret(), nil
-		})
-		if err != nil || !token.Valid {
-			return c.JSON(http.StatusUnauthorized, echo.Map{"error": "invalid or expired token"})
-		}
-
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			return c.JSON(http.StatusUnauthorized, echo.Map{"error": "invalid token claims"})
-		}
-
-		// Check SessionsClearedAt: if user cleared all sessions, iat must be newer
-		if sub, ok := claims["sub"].(float64); ok {
-			userID := uint(sub)
-			var user models.User
-			if database.DB.Select("id, sessions_cleared_at, banned").First(&user, userID).Error == nil {
-				if user.Banned {
-					return c.JSON(http.StatusForbidden, echo.Map{"error": "account is banned"})
-				}
-				if user.SessionsClearedAt != nil {
-					iatF, _ := claims["iat"].(float64)
-					iat := time.Unix(int64(iatF), 0)
-					if iat.Before(*user.SessionsClearedAt) {
-						return c.JSON(http.StatusUnauthorized, echo.Map{"error": "session invalidated"})
-					}
-				}
-			}
+		claims, status, msg := bearerClaims(c)
+		if claims == nil {
+			return c.JSON(status, echo.Map{"error": msg})
 		}
 
 		c.Set("user_id", claims["sub"])
@@ -76,6 +42,73 @@ func JWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
+// OptionalAuth identifies the caller like JWTMiddleware when valid credentials are sent,
+// and otherwise continues anonymously. Public routes use it to show owner-only data
+// without rejecting visitors that carry a stale token.
+func OptionalAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if key := c.Request().Header.Get("X-API-Key"); key != "" {
+			var user models.User
+			if database.DB.Where("api_token = ? AND banned = false", key).First(&user).Error == nil {
+				c.Set("user_id", float64(user.ID))
+				c.Set("username", user.Username)
+				c.Set("role", user.Role)
+			}
+			return next(c)
+		}
+		if claims, _, _ := bearerClaims(c); claims != nil {
+			c.Set("user_id", claims["sub"])
+			c.Set("username", claims["username"])
+			c.Set("role", claims["role"])
+		}
+		return next(c)
+	}
+}
+
+// bearerClaims parses and validates the Bearer token of the request.
+// On failure claims is nil and status/msg describe the rejection.
+func bearerClaims(c echo.Context) (claims jwt.MapClaims, status int, msg string) {
+	auth := c.Request().Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return nil, http.StatusUnauthorized, "missing or invalid authorization header"
+	}
+	tokenStr := strings.TrimPrefix(auth, "Bearer ")
+
+	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return jwtSecret(), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, http.StatusUnauthorized, "invalid or expired token"
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, http.StatusUnauthorized, "invalid token claims"
+	}
+
+	// Check SessionsClearedAt: if user cleared all sessions, iat must be newer
+	if sub, ok := claims["sub"].(float64); ok {
+		userID := uint(sub)
+		var user models.User
+		if database.DB.Select("id, sessions_cleared_at, banned").First(&user, userID).Error == nil {
+			if user.Banned {
+				return nil, http.StatusForbidden, "account is banned"
+			}
+			if user.SessionsClearedAt != nil {
+				iatF, _ := claims["iat"].(float64)
+				iat := time.Unix(int64(iatF), 0)
+				if iat.Before(*user.SessionsClearedAt) {
+					return nil, http.StatusUnauthorized, "session invalidated"
+				}
+			}
+		}
+	}
+	return claims, 0, ""
+}
+
 func apiKeyAuth(c echo.Context, key string, next echo.HandlerFunc) error {
 	var user models.User
 	if err := database.DB.Where("api_token = ? AND banned = false", key).First(&user).Error; err != nil {
@@ -87,6 +120,22 @@ func apiKeyAuth(c echo.Context, key string, next echo.HandlerFunc) error {
 	return next(c)
 }
 
+// WSQueryAuth copies ?token= / ?key= query params into the Authorization / X-API-Key
+// headers so JWTMiddleware can be reused on WebSocket routes: the browser WebSocket API
+// cannot set custom headers on the handshake request.
+func WSQueryAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		if t := c.QueryParam("token"); t != "" && req.Header.Get("Authorization") == "" {
+			req.Header.Set("Authorization", "Bearer "+t)
+		}
+		if k := c.QueryParam("key"); k != "" && req.Header.Get("X-API-Key") == "" {
+			req.Header.Set("X-API-Key", k)
+		}
+		return next(c)
+	}
+}
+
 // AdminMiddleware requires role == "admin"
 func AdminMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
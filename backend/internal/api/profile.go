@@ -199,6 +199,10 @@ func PurgeScheduledDeletions() {
 		return
 	}
 	for _, u := range users {
+		// Grants held by the user and grants on the user's servers
+		database.DB.Where("user_id = ? OR server_id IN (?)", u.ID,
+			database.DB.Model(&models.Server{}).Select("id").Where("owner_id = ?", u.ID)).
+			Delete(&models.RCONAccess{})
 		database.DB.Where("owner_id = ?", u.ID).Delete(&models.Server{})
 		database.DB.Delete(&models.User{}, u.ID)
 	}
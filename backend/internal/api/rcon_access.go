@@ -0,0 +1,119 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// canUseRCON — владелец, администратор или пользователь с делегированным доступом
+func canUseRCON(userID uint, role string, server *models.Server) bool {
+	if userID == 0 {
+		return false
+	}
+	if role == "admin" || server.OwnerID == userID {
+		return true
+	}
+	var count int64
+	database.DB.Model(&models.RCONAccess{}).
+		Where("server_id = ? AND user_id = ?", server.ID, userID).
+		Count(&count)
+	return count > 0
+}
+
+// rconManagedServer загружает сервер, доступом к RCON которого может управлять текущий пользователь.
+// Сейчас это владелец или админ — как у managedServer.
+func rconManagedServer(c echo.Context) (*models.Server, error) {
+	return managedServer(c)
+}
+
+// GetRCONAccess GET /api/v1/servers/:id/rcon/access — список пользователей с делегированным RCON
+func GetRCONAccess(c echo.Context) error {
+	server, errResp := rconManagedServer(c)
+	if server == nil {
+		return errResp
+	}
+
+	type accessEntry struct {
+		models.RCONAccess
+		Username string `json:"username"`
+	}
+	var rows []accessEntry
+	database.DB.Model(&models.RCONAccess{}).
+		Select("rcon_accesses.*, COALESCE(users.username, '') AS username").
+		Joins("LEFT JOIN users ON users.id = rcon_accesses.user_id").
+		Where("rcon_accesses.server_id = ?", server.ID).
+		Order("rcon_accesses.created_at ASC").
+		Scan(&rows)
+	if rows == nil {
+		rows = []accessEntry{}
+	}
+	return c.JSON(http.StatusOK, rows)
+}
+
+// GrantRCONAccess POST /api/v1/servers/:id/rcon/access — выдать доступ по username или user_id
+func GrantRCONAccess(c echo.Context) error {
+	server, errResp := rconManagedServer(c)
+	if server == nil {
+		return errResp
+	}
+
+	var req struct {
+		UserID   uint   `json:"user_id"`
+		Username string `json:"username"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+
+	var user models.User
+	q := database.DB.Select("id, username")
+	switch {
+	case req.UserID != 0:
+		q = q.Where("id = ?", req.UserID)
+	case strings.TrimSpace(req.Username) != "":
+		q = q.Where("username = ?", strings.TrimSpace(req.Username))
+	default:
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "user_id or username required"})
+	}
+	if err := q.First(&user).Error; err != nil {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "user not found"})
+	}
+	if user.ID == server.OwnerID {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "owner already has access"})
+	}
+
+	aid, aname := actorFromCtx(c)
+	access := models.RCONAccess{ServerID: server.ID, UserID: user.ID, GrantedBy: aid}
+	if err := database.DB.Where("server_id = ? AND user_id = ?", server.ID, user.ID).
+		FirstOrCreate(&access).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+	}
+	logAudit(aid, aname, "grant_rcon", "server", server.ID, user.Username)
+	return c.JSON(http.StatusOK, access)
+}
+
+// RevokeRCONAccess DELETE /api/v1/servers/:id/rcon/access/:userID — отозвать доступ
+func RevokeRCONAccess(c echo.Context) error {
+	server, errResp := rconManagedServer(c)
+	if server == nil {
+		return errResp
+	}
+	userID, err := strconv.Atoi(c.Param("userID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid user id"})
+	}
+
+	result := database.DB.Where("server_id = ? AND user_id = ?", server.ID, userID).Delete(&models.RCONAccess{})
+	if result.RowsAffected == 0 {
+		return c.JSON(http.StatusNotFound, echo.Map{"error": "access not found"})
+	}
+	aid, aname := actorFromCtx(c)
+	logAudit(aid, aname, "revoke_rcon", "server", server.ID, c.Param("userID"))
+	return c.JSON(http.StatusOK, echo.Map{"ok": true})
+}
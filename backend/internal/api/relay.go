@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// relayInterval — как часто реплика-последователь проверяет свежие статусы в БД
+const relayInterval = 2 * time.Second
+
+// relayOverlap — насколько назад от курсора перечитываются статусы: last_update ставится
+// до записи, и строка, закоммиченная позже соседней, может оказаться старше курсора
+const relayOverlap = 10 * time.Second
+
+// RelayStatusUpdates рассылает WebSocket-клиентам этой реплики статусы, записанные лидером.
+// Работает, пока реплика не лидер: у лидера обновления приходят напрямую из опросчика.
+func RelayStatusUpdates(ctx context.Context) {
+	ticker := time.NewTicker(relayInterval)
+	defer ticker.Stop()
+
+	var latest sql.NullTime
+	database.DB.Model(&models.ServerStatus{}).Select("MAX(last_update)").Row().Scan(&latest) //nolint:errcheck
+	cur := newRelayCursor(latest.Time)
+	for {
+		select {
+		case <-ticker.C:
+			var statuses []models.ServerStatus
+			database.DB.Where("last_update > ?", cur.since.Add(-relayOverlap)).Order("last_update").Find(&statuses)
+			for _, st := range cur.fresh(statuses) {
+				WSHub.BroadcastUpdate(st.ServerID, st)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// relayCursor помнит, докуда статусы уже разосланы. Курсор двигается только по значениям
+// last_update из БД: их пишет лидер по своим часам, и сравнивать их с часами этой реплики нельзя.
+type relayCursor struct {
+	since time.Time
+	// sent — last_update, уже разосланный по каждому серверу: окно перекрытия
+	// возвращает те же строки повторно
+	sent map[uint]time.Time
+}
+
+func newRelayCursor(since time.Time) *relayCursor {
+	return &relayCursor{since: since, sent: make(map[uint]time.Time)}
+}
+
+// fresh сдвигает курсор по прочитанным статусам и возвращает те, что ещё не рассылались
+func (c *relayCursor) fresh(statuses []models.ServerStatus) []*models.ServerStatus {
+	var out []*models.ServerStatus
+	for i := range statuses {
+		st := &statuses[i]
+		if st.LastUpdate.After(c.since) {
+			c.since = st.LastUpdate
+		}
+		if prev, ok := c.sent[st.ServerID]; ok && !st.LastUpdate.After(prev) {
+			continue
+		}
+		c.sent[st.ServerID] = st.LastUpdate
+		out = append(out, st)
+	}
+	return out
+}
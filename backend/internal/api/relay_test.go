@@ -0,0 +1,50 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+func TestRelayCursor(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time { return base.Add(time.Duration(sec) * time.Second) }
+	st := func(id uint, sec int) models.ServerStatus {
+		return models.ServerStatus{ServerID: id, LastUpdate: at(sec)}
+	}
+	ids := func(out []*models.ServerStatus) []uint {
+		var r []uint
+		for _, s := range out {
+			r = append(r, s.ServerID)
+		}
+		return r
+	}
+
+	cur := newRelayCursor(at(0))
+	if got := ids(cur.fresh([]models.ServerStatus{st(1, 1), st(2, 3)})); !reflect.DeepEqual(got, []uint{1, 2}) {
+		t.Fatalf("first read = %v, want [1 2]", got)
+	}
+	if !cur.since.Equal(at(3)) {
+		t.Fatalf("since = %v, want %v", cur.since, at(3))
+	}
+
+	// Окно перекрытия возвращает уже разосланные строки и строку, закоммиченную позже,
+	// но с last_update старше курсора: разослать нужно только её
+	got := ids(cur.fresh([]models.ServerStatus{st(1, 1), st(3, 2), st(2, 3)}))
+	if !reflect.DeepEqual(got, []uint{3}) {
+		t.Fatalf("overlap read = %v, want [3]", got)
+	}
+	if !cur.since.Equal(at(3)) {
+		t.Fatalf("since moved back to %v", cur.since)
+	}
+
+	// Новый статус того же сервера рассылается снова
+	if got := ids(cur.fresh([]models.ServerStatus{st(2, 3), st(1, 5)})); !reflect.DeepEqual(got, []uint{1}) {
+		t.Fatalf("update read = %v, want [1]", got)
+	}
+	if !cur.since.Equal(at(5)) {
+		t.Fatalf("since = %v, want %v", cur.since, at(5))
+	}
+}
@@ -130,6 +130,9 @@ func GetAdminSettings(c echo.Context) error {
 		"vrising_free_plot_icon_set":  s.VRisingFreePlotIcon != "",
 		"vrising_hide_admins":         s.VRisingHideAdmins,
 		"maintenance_mode":            s.MaintenanceMode,
+		"history_raw_retention_days":  s.HistoryRawRetentionDays,
+		"history_5m_retention_days":   s.History5mRetentionDays,
+		"history_1h_retention_days":   s.History1hRetentionDays,
 	})
 }
 
@@ -168,6 +171,9 @@ func UpdateSettings(c echo.Context) error {
 		SSLMode                 string `json:"ssl_mode"`   // none|letsencrypt|custom
 		SSLDomain               string `json:"ssl_domain"`
 		MaintenanceMode         *bool  `json:"maintenance_mode"`
+		HistoryRawRetentionDays *int   `json:"history_raw_retention_days"` // 0 = keep forever
+		History5mRetentionDays  *int   `json:"history_5m_retention_days"`
+		History1hRetentionDays  *int   `json:"history_1h_retention_days"`
 	}
 	if err := c.Bind(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid payload"})
@@ -289,6 +295,14 @@ func UpdateSettings(c echo.Context) error {
 		}
 		s.VRisingFreePlotIcon = payload.VRisingFreePlotIcon
 	}
+	for _, d := range []*int{payload.HistoryRawRetentionDays, payload.History5mRetentionDays, payload.History1hRetentionDays} {
+		if d != nil && (*d < 0 || *d > 3650) {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "history retention must be 0-3650 days"})
+		}
+	}
+	if payload.HistoryRawRetentionDays != nil { s.HistoryRawRetentionDays = *payload.HistoryRawRetentionDays }
+	if payload.History5mRetentionDays != nil { s.History5mRetentionDays = *payload.History5mRetentionDays }
+	if payload.History1hRetentionDays != nil { s.History1hRetentionDays = *payload.History1hRetentionDays }
 	if payload.DiscordRefreshInterval >= 10 {
 		s.DiscordRefreshInterval = payload.DiscordRefreshInterval
 	} else if payload.DiscordRefreshInterval == 0 && s.DiscordRefreshInterval == 0 {
@@ -328,26 +342,34 @@ func GetLogo(c echo.Context) error {
 		return c.NoContent(http.StatusNotFound)
 	}
 
-	// Expected format: "data:<mime>;base64,<data>"
-	comma := strings.Index(s.LogoData, ",")
-	if comma < 0 {
+	mime, data, ok := decodeDataURL(s.LogoData)
+	if !ok {
 		return c.NoContent(http.StatusNotFound)
 	}
-	header := s.LogoData[:comma]    // "data:image/webp;base64"
-	encoded := s.LogoData[comma+1:] // base64 payload
 
-	mime := "image/png"
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.Blob(http.StatusOK, mime, data)
+}
+
+// decodeDataURL splits a base64 data URL ("data:<mime>;base64,<data>") into its MIME type and bytes.
+func decodeDataURL(dataURL string) (mime string, data []byte, ok bool) {
+	comma := strings.Index(dataURL, ",")
+	if comma < 0 {
+		return "", nil, false
+	}
+	header := dataURL[:comma]    // "data:image/webp;base64"
+	encoded := dataURL[comma+1:] // base64 payload
+
+	mime = "image/png"
 	if semi := strings.Index(header, ";"); semi > 5 {
 		mime = header[5:semi] // strip "data:"
 	}
 
 	data, err := base64.StdEncoding.DecodeString(encoded)
 	if err != nil {
-		return c.NoContent(http.StatusInternalServerError)
+		return "", nil, false
 	}
-
-	c.Response().Header().Set("Cache-Control", "public, max-age=300")
-	return c.Blob(http.StatusOK, mime, data)
+	return mime, data, true
 }
 
 // effectiveIconURL returns the API endpoint if the icon data is present, otherwise empty string.
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+	"github.com/RJ-Bond/js-monitoring/internal/sla"
+)
+
+const (
+	// slaMaxWindow — самый длинный период, за который считается аптайм
+	slaMaxWindow = 400 * 24 * time.Hour
+	// slaMaxExclusions — сколько интервалов можно исключить одним запросом
+	slaMaxExclusions = 50
+)
+
+// parseBound разбирает границу периода: RFC3339 или дата YYYY-MM-DD по местному времени.
+// Дата в конце периода (end) включается целиком.
+func parseBound(v string, end bool) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.Local(), true
+	}
+	t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, true
+}
+
+// monthWindow — календарный месяц YYYY-MM по местному времени
+func monthWindow(v string) (time.Time, time.Time, bool) {
+	t, err := time.ParseInLocation("2006-01", v, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return t, t.AddDate(0, 1, 0), true
+}
+
+// uptimeWindow разбирает период запроса аптайма:
+// ?period=24h|7d|30d|month (month — текущий месяц до этого момента), ?month=YYYY-MM или ?from=&to=
+func uptimeWindow(c echo.Context) (period string, from, to time.Time, errMsg string) {
+	now := time.Now()
+	to = now
+	switch {
+	case c.QueryParam("month") != "":
+		var ok bool
+		period = c.QueryParam("month")
+		if from, to, ok = monthWindow(period); !ok {
+			return "", from, to, "month must be YYYY-MM"
+		}
+	case c.QueryParam("from") != "":
+		period = "custom"
+		var ok bool
+		if from, ok = parseBound(c.QueryParam("from"), false); !ok {
+			return "", from, to, "from must be RFC3339 or YYYY-MM-DD"
+		}
+		if v := c.QueryParam("to"); v != "" {
+			if to, ok = parseBound(v, true); !ok {
+				return "", from, to, "to must be RFC3339 or YYYY-MM-DD"
+			}
+		}
+	default:
+		period = c.QueryParam("period")
+		switch period {
+		case "7d":
+			from = now.Add(-7 * 24 * time.Hour)
+		case "30d":
+			from = now.Add(-30 * 24 * time.Hour)
+		case "month":
+			from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+		default:
+			period = "24h"
+			from = now.Add(-24 * time.Hour)
+		}
+	}
+
+	if to.After(now) {
+		to = now
+	}
+	if !from.Before(to) {
+		return "", from, to, "period is empty or in the future"
+	}
+	if to.Sub(from) > slaMaxWindow {
+		return "", from, to, "period is too long (max 400 days)"
+	}
+	return period, from, to, ""
+}
+
+// uptimeExclusions разбирает ?exclude=from/to,from/to — интервалы, не входящие в расчёт
+// вдобавок к окнам плановых работ (например, работы, не внесённые заранее)
+func uptimeExclusions(c echo.Context) ([]sla.Interval, string) {
+	v := c.QueryParam("exclude")
+	if v == "" {
+		return nil, ""
+	}
+	parts := strings.Split(v, ",")
+	if len(parts) > slaMaxExclusions {
+		return nil, fmt.Sprintf("too many exclusions (max %d)", slaMaxExclusions)
+	}
+	out := make([]sla.Interval, 0, len(parts))
+	for _, p := range parts {
+		a, b, ok := strings.Cut(strings.TrimSpace(p), "/")
+		if !ok {
+			return nil, "exclude must be a list of from/to intervals"
+		}
+		from, okFrom := parseBound(a, false)
+		to, okTo := parseBound(b, true)
+		if !okFrom || !okTo || !from.Before(to) {
+			return nil, "invalid exclude interval: " + p
+		}
+		out = append(out, sla.Interval{From: from, To: to})
+	}
+	return out, ""
+}
+
+// slaRow — строка месячного отчёта
+type slaRow struct {
+	ServerID  uint   `json:"server_id"`
+	Title     string `json:"title"`
+	GameType  string `json:"game_type"`
+	Incidents int64  `json:"incidents"`
+	sla.Report
+}
+
+// csvCell экранирует ячейку, которую табличный редактор принял бы за формулу
+func csvCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// GetSLAReport GET /api/v1/admin/reports/sla?month=YYYY-MM&format=json|csv&exclude=from/to,...&include_maintenance=1
+// Аптайм всех серверов за календарный месяц (по умолчанию — прошлый). include_maintenance=1 —
+// сырой аптайм: простой во время плановых работ не исключается.
+func GetSLAReport(c echo.Context) error {
+	month := c.QueryParam("month")
+	if month == "" {
+		month = time.Now().AddDate(0, -1, 0).Format("2006-01")
+	}
+	from, to, ok := monthWindow(month)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "month must be YYYY-MM"})
+	}
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+	if !from.Before(to) {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "month is in the future"})
+	}
+	exclude, msg := uptimeExclusions(c)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": msg})
+	}
+
+	var servers []models.Server
+	database.DB.Order("id ASC").Find(&servers)
+
+	var counts []struct {
+		ServerID uint
+		N        int64
+	}
+	database.DB.Model(&models.ServerIncident{}).
+		Select("server_id, COUNT(*) AS n").
+		Where("started_at >= ? AND started_at < ?", from, to).
+		Group("server_id").
+		Scan(&counts)
+	incidents := map[uint]int64{}
+	for _, cnt := range counts {
+		incidents[cnt.ServerID] = cnt.N
+	}
+
+	compute := sla.Compute
+	includeMaintenance := c.QueryParam("include_maintenance") == "1"
+	if includeMaintenance {
+		compute = sla.ComputeRaw
+	}
+	rows := make([]slaRow, 0, len(servers))
+	for i := range servers {
+		s := &servers[i]
+		rows = append(rows, slaRow{
+			ServerID:  s.ID,
+			Title:     s.Title,
+			GameType:  s.GameType,
+			Incidents: incidents[s.ID],
+			Report:    compute(s, from, to, exclude),
+		})
+	}
+
+	if c.QueryParam("format") != "csv" {
+		return c.JSON(http.StatusOK, echo.Map{
+			"month": month, "from": from, "to": to, "include_maintenance": includeMaintenance, "servers": rows,
+		})
+	}
+
+	c.Response().Header().Set("Content-Type", "text/csv; charset=utf-8")
+	c.Response().Header().Set("Content-Disposition", "attachment; filename=sla-"+month+".csv")
+	c.Response().WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(c.Response().Writer)
+	_ = w.Write([]string{"ID", "Title", "Game", "Uptime %", "Observed Seconds", "Downtime Seconds", "Excluded Seconds", "Incidents"})
+	for _, r := range rows {
+		uptime := ""
+		if r.Uptime != nil {
+			uptime = fmt.Sprintf("%.3f", *r.Uptime)
+		}
+		_ = w.Write([]string{
+			fmt.Sprint(r.ServerID),
+			csvCell(r.Title),
+			csvCell(r.GameType),
+			uptime,
+			fmt.Sprint(r.ObservedSeconds),
+			fmt.Sprint(r.DowntimeSeconds),
+			fmt.Sprint(r.ExcludedSeconds),
+			fmt.Sprint(r.Incidents),
+		})
+	}
+	w.Flush()
+	return nil
+}
@@ -2,10 +2,8 @@ package api
 
 import (
 	"encoding/json"
-	"fmt"
 	"log"
 	"net/http"
-	"os"
 	"sync"
 
 	"github.com/gorilla/websocket"
@@ -13,6 +11,7 @@ import (
 
 	"github.com/RJ-Bond/js-monitoring/internal/database"
 	"github.com/RJ-Bond/js-monitoring/internal/models"
+	"github.com/RJ-Bond/js-monitoring/internal/rcon"
 )
 
 var upgrader = websocket.Upgrader{
@@ -109,16 +108,12 @@ func HandleWebSocket(c echo.Context) error {
 	return nil
 }
 
-// HandleRCON GET /api/v1/rcon — защищённый WS-терминал
+// HandleRCON GET /api/v1/rcon — защищённый WS-терминал.
+// Аутентификация — JWTMiddleware (токен можно передать в ?token= / ?key=, см. WSQueryAuth).
+// Доступ: владелец сервера, администратор или пользователь с делегированным RCONAccess.
 func HandleRCON(c echo.Context) error {
-	// Проверка API ключа
-	apiKey := c.QueryParam("key")
-	if apiKey == "" {
-		apiKey = c.Request().Header.Get("X-API-Key")
-	}
-	if apiKey != os.Getenv("API_SECRET_KEY") {
-		return c.JSON(http.StatusUnauthorized, echo.Map{"error": "unauthorized"})
-	}
+	actorID, actorName := actorFromCtx(c)
+	role, _ := c.Get("role").(string)
 
 	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
 	if err != nil {
@@ -140,13 +135,45 @@ func HandleRCON(c echo.Context) error {
 		conn.WriteJSON(echo.Map{"error": "server not found"}) //nolint:errcheck
 		return nil
 	}
+	if !canUseRCON(actorID, role, &server) {
+		conn.WriteJSON(echo.Map{"error": "rcon access denied"}) //nolint:errcheck
+		return nil
+	}
+
+	variant, err := rcon.VariantFor(server.GameType)
+	if err != nil {
+		conn.WriteJSON(echo.Map{"error": "rcon is not supported for " + server.GameType}) //nolint:errcheck
+		return nil
+	}
+	if server.SecretRCON == "" {
+		conn.WriteJSON(echo.Map{"error": "rcon password is not set"}) //nolint:errcheck
+		return nil
+	}
+	rconPort := server.RCONPort
+	if rconPort == 0 {
+		rconPort = rcon.DefaultPort(variant, server.Port)
+	}
+	dial := func() (*rcon.Client, error) {
+		return rcon.Dial(variant, server.IP, rconPort, server.SecretRCON, rcon.DefaultTimeout)
+	}
+
+	client, err := dial()
+	if err != nil {
+		conn.WriteJSON(echo.Map{"error": err.Error()}) //nolint:errcheck
+		return nil
+	}
+	defer func() {
+		if client != nil {
+			client.Close()
+		}
+	}()
 
 	conn.WriteJSON(echo.Map{ //nolint:errcheck
 		"status": "connected",
 		"server": server.Title,
 	})
 
-	// Обработка RCON команд
+	// Обработка RCON команд: каждый пакет ответа сразу уходит клиенту отдельным сообщением
 	for {
 		var cmdMsg struct {
 			Command string `json:"command"`
@@ -154,10 +181,30 @@ func HandleRCON(c echo.Context) error {
 		if err := conn.ReadJSON(&cmdMsg); err != nil {
 			break
 		}
+		if cmdMsg.Command == "" {
+			continue
+		}
 
-		// TODO: реализовать реальный RCON (Source RCON Protocol / Minecraft RCON)
-		response := fmt.Sprintf("Sent to %s: %s", server.Title, cmdMsg.Command)
-		conn.WriteJSON(echo.Map{"output": response}) //nolint:errcheck
+		// Соединение могло быть закрыто сервером (рестарт, таймаут простоя) — переподключаемся
+		if client == nil {
+			if client, err = dial(); err != nil {
+				conn.WriteJSON(echo.Map{"error": err.Error()}) //nolint:errcheck
+				continue
+			}
+		}
+
+		logAudit(actorID, actorName, "rcon_command", "server", server.ID, cmdMsg.Command)
+		err := client.Execute(cmdMsg.Command, func(chunk string) {
+			conn.WriteJSON(echo.Map{"output": chunk}) //nolint:errcheck
+		})
+		if err != nil {
+			log.Printf("[RCON] server %d: %v", server.ID, err)
+			conn.WriteJSON(echo.Map{"error": err.Error()}) //nolint:errcheck
+			client.Close()
+			client = nil
+			continue
+		}
+		conn.WriteJSON(echo.Map{"done": true}) //nolint:errcheck
 	}
 
 	return nil
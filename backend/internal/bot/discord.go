@@ -15,7 +15,10 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/history"
+	"github.com/RJ-Bond/js-monitoring/internal/maintenance"
 	"github.com/RJ-Bond/js-monitoring/internal/models"
+	"github.com/RJ-Bond/js-monitoring/internal/sla"
 )
 
 const botVersion = "v2.4.0"
@@ -621,6 +624,70 @@ func (b *DiscordBot) logoURL() string {
 	return strings.TrimRight(b.appURL, "/") + "/api/v1/logo"
 }
 
+// faviconURL returns the public URL of the server icon, or "" when the server has none stored.
+func (b *DiscordBot) faviconURL(serverID uint) string {
+	if b.appURL == "" {
+		return ""
+	}
+	var count int64
+	b.db.Model(&models.ServerFavicon{}).Where("server_id = ?", serverID).Count(&count)
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/api/v1/servers/%d/favicon", strings.TrimRight(b.appURL, "/"), serverID)
+}
+
+// motdANSIColors maps Minecraft color names to the ANSI codes Discord renders in ```ansi blocks.
+var motdANSIColors = map[string]int{
+	"black": 30, "dark_gray": 30,
+	"dark_red": 31, "red": 31,
+	"dark_green": 32, "green": 32,
+	"gold": 33, "yellow": 33,
+	"dark_blue": 34, "blue": 34,
+	"dark_purple": 35, "light_purple": 35,
+	"dark_aqua": 36, "aqua": 36,
+	"gray": 37, "white": 37,
+}
+
+// statusMOTD returns the stored MOTD spans of an online server.
+func statusMOTD(srv *models.Server) string {
+	if srv.Status == nil || !srv.Status.OnlineStatus {
+		return ""
+	}
+	return srv.Status.MOTDSpans
+}
+
+// motdANSI renders stored MOTD spans as a Discord ```ansi code block.
+// Returns "" when the status has no styled MOTD.
+func motdANSI(spansJSON string) string {
+	if spansJSON == "" {
+		return ""
+	}
+	var spans []models.MOTDSpan
+	if err := json.Unmarshal([]byte(spansJSON), &spans); err != nil || len(spans) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("```ansi\n")
+	for _, sp := range spans {
+		codes := []string{"0"}
+		if sp.Bold {
+			codes = append(codes, "1")
+		}
+		if sp.Underlined {
+			codes = append(codes, "4")
+		}
+		if c, ok := motdANSIColors[sp.Color]; ok {
+			codes = append(codes, strconv.Itoa(c))
+		}
+		// Backticks would close the code block early.
+		text := strings.ReplaceAll(sp.Text, "`", "'")
+		sb.WriteString("\x1b[" + strings.Join(codes, ";") + "m" + text)
+	}
+	sb.WriteString("\x1b[0m\n```")
+	return sb.String()
+}
+
 // countryFlag converts an ISO 3166-1 alpha-2 code (e.g. "RU") to a flag emoji (e.g. 🇷🇺).
 func countryFlag(code string) string {
 	if len(code) != 2 {
@@ -639,6 +706,7 @@ func gameDisplayName(gameType string) string {
 		"minecraft":         "Minecraft",
 		"minecraft_bedrock": "Minecraft Bedrock",
 		"fivem":             "FiveM",
+		"redm":              "RedM",
 		"gmod":              "Garry's Mod",
 		"valheim":           "Valheim",
 		"dayz":              "DayZ",
@@ -943,6 +1011,11 @@ func (b *DiscordBot) startAlertChecker(ctx context.Context) {
 						color = 0x57F287
 						titleEmoji = "🟢"
 						statusWord = "вернулся онлайн"
+					} else if maintenance.Active(st.ServerID, now) {
+						// Scheduled maintenance: announce it as such rather than as an outage.
+						color = 0xE67E22
+						titleEmoji = "🛠"
+						statusWord = "недоступен: идут плановые работы"
 					} else {
 						color = 0xED4245
 						titleEmoji = "🔴"
@@ -998,6 +1071,9 @@ func (b *DiscordBot) buildServerEmbed(srv *models.Server, period string) *discor
 	if online {
 		statusText = "🟢 В сети"
 		color = 0x57F287
+	} else if maintenance.Active(srv.ID, time.Now()) {
+		statusText = "🛠 Обслуживание"
+		color = 0xE67E22
 	}
 	// Override with per-server custom color if set (e.g. "#FF5500" or "FF5500")
 	if srv.DiscordColor != "" {
@@ -1062,25 +1138,8 @@ func (b *DiscordBot) buildServerEmbed(srv *models.Server, period string) *discor
 		periodLabel = "24ч"
 	}
 
-	var peak int
-	b.db.Model(&models.PlayerHistory{}).
-		Select("COALESCE(MAX(count), 0)").
-		Where("server_id = ? AND timestamp > ? AND is_online = true", srv.ID, sinceTime).
-		Scan(&peak)
-
-	var totalH, onlineH int64
-	b.db.Model(&models.PlayerHistory{}).
-		Where("server_id = ? AND timestamp > ?", srv.ID, sinceTime).
-		Count(&totalH)
-	b.db.Model(&models.PlayerHistory{}).
-		Where("server_id = ? AND timestamp > ? AND is_online = true", srv.ID, sinceTime).
-		Count(&onlineH)
-
-	var avgOnline float64
-	b.db.Model(&models.PlayerHistory{}).
-		Select("COALESCE(AVG(count), 0)").
-		Where("server_id = ? AND timestamp > ? AND is_online = true", srv.ID, sinceTime).
-		Scan(&avgOnline)
+	// Long periods come from history rollups; raw points are only kept for a few days.
+	stats := history.Summarize(srv.ID, sinceTime, history.Resolution(now.Sub(sinceTime)))
 
 	// Unique players over the selected period from PlayerSession.
 	var uniqueToday int64
@@ -1089,12 +1148,13 @@ func (b *DiscordBot) buildServerEmbed(srv *models.Server, period string) *discor
 		Where("server_id = ? AND started_at >= ?", srv.ID, sinceTime).
 		Scan(&uniqueToday)
 
+	// Uptime is time-weighted: poll intervals differ between busy and empty servers.
 	uptimeVal := "—"
-	if totalH > 0 {
-		uptimeVal = fmt.Sprintf("%d%%", onlineH*100/totalH)
+	if up := sla.Compute(srv, sinceTime, now, nil).Uptime; up != nil {
+		uptimeVal = fmt.Sprintf("%d%%", int(*up))
 	}
-	peakVal := fmt.Sprintf("%d", peak)
-	avgVal := fmt.Sprintf("%d", int(avgOnline))
+	peakVal := fmt.Sprintf("%d", stats.Peak)
+	avgVal := fmt.Sprintf("%d", int(stats.AvgOnline))
 	uniqueVal := fmt.Sprintf("%d", uniqueToday)
 
 	// Load site settings (used for site name + embed field config).
@@ -1108,7 +1168,8 @@ func (b *DiscordBot) buildServerEmbed(srv *models.Server, period string) *discor
 	// Parse embed field visibility config (all fields shown by default).
 	embedCfg := models.DefaultEmbedFieldConfig()
 	if settings.DiscordEmbedConfig != "" {
-		var c models.EmbedFieldConfig
+		// Start from defaults so fields added later stay visible with an older saved config.
+		c := models.DefaultEmbedFieldConfig()
 		if err := json.Unmarshal([]byte(settings.DiscordEmbedConfig), &c); err == nil {
 			embedCfg = c
 		}
@@ -1150,6 +1211,17 @@ func (b *DiscordBot) buildServerEmbed(srv *models.Server, period string) *discor
 		fields = append(fields, &discordgo.MessageEmbedField{Name: fmt.Sprintf("👤 Игроков за %s", periodLabel), Value: uniqueVal, Inline: false})
 	}
 
+	// Tracked server rules (A2S_RULES), e.g. Rust wipe date or DayZ time of day.
+	if embedCfg.Rules && online {
+		var rules []models.ServerRule
+		b.db.Where("server_id = ?", srv.ID).Order("`key` ASC").Limit(10).Find(&rules)
+		for _, r := range rules {
+			if r.Value != "" {
+				fields = append(fields, &discordgo.MessageEmbedField{Name: "⚙️ " + r.Key, Value: r.Value, Inline: true})
+			}
+		}
+	}
+
 	// Player list from active sessions (ended_at IS NULL)
 	if embedCfg.PlayerList && online && srv.Status != nil && srv.Status.PlayersNow > 0 {
 		var sessions []models.PlayerSession
@@ -1181,6 +1253,7 @@ func (b *DiscordBot) buildServerEmbed(srv *models.Server, period string) *discor
 	}
 
 	embed := &discordgo.MessageEmbed{
+		Description: motdANSI(statusMOTD(srv)),
 		Author: &discordgo.MessageEmbedAuthor{
 			Name: siteName,
 			URL:  strings.TrimRight(b.appURL, "/") + "/",
@@ -1195,6 +1268,10 @@ func (b *DiscordBot) buildServerEmbed(srv *models.Server, period string) *discor
 		Timestamp: now.Format(time.RFC3339),
 	}
 
+	if iconURL := b.faviconURL(srv.ID); iconURL != "" {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: iconURL}
+	}
+
 	if b.appURL != "" {
 		base := strings.TrimRight(b.appURL, "/")
 		imageURL := fmt.Sprintf("%s/api/v1/chart/%d?period=%s&_t=%d", base, srv.ID, period, now.Unix())
@@ -55,6 +55,7 @@ func AutoMigrate() error {
 		&models.Server{},
 		&models.ServerStatus{},
 		&models.PlayerHistory{},
+		&models.PlayerHistoryRollup{},
 		&models.AlertsConfig{},
 		&models.NewsTag{},
 		&models.NewsItem{},
@@ -72,5 +73,14 @@ func AutoMigrate() error {
 		&models.VRisingMute{},
 		&models.VRisingWarning{},
 		&models.VRisingAnnouncement{},
+		&models.RCONAccess{},
+		&models.ServerRule{},
+		&models.ServerFavicon{},
+		&models.PollAgent{},
+		&models.RegionStatus{},
+		&models.LeaderLease{},
+		&models.ServerIncident{},
+		&models.MaintenanceWindow{},
+		&models.AlertRule{},
 	)
 }
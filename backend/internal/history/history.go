@@ -0,0 +1,190 @@
+// Package history читает и сворачивает историю онлайна серверов. Поллер пишет сырую точку
+// PlayerHistory на каждый опрос; Rollup сворачивает их в 5-минутные и часовые агрегаты
+// (PlayerHistoryRollup), ApplyRetention удаляет старое по срокам хранения из настроек.
+// Points и Summarize читают период из подходящего разрешения, дочитывая ещё не свёрнутый
+// хвост из более мелкого.
+package history
+
+import (
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// Raw — разрешение сырых точек PlayerHistory
+const Raw = "raw"
+
+type level struct {
+	name string
+	step time.Duration
+}
+
+// levels — разрешения агрегатов от мелкого к крупному; каждое сворачивается из предыдущего
+var levels = []level{
+	{models.HistoryResolution5m, 5 * time.Minute},
+	{models.HistoryResolution1h, time.Hour},
+}
+
+// Retention — сроки хранения истории по разрешениям, дни (0 — бессрочно)
+type Retention struct {
+	Raw     int
+	FiveMin int
+	Hourly  int
+}
+
+// LoadRetention читает сроки хранения из настроек сайта
+func LoadRetention() Retention {
+	var s models.SiteSettings
+	if database.DB.First(&s, 1).Error != nil {
+		return Retention{Raw: 3, FiveMin: 30, Hourly: 365}
+	}
+	return Retention{Raw: s.HistoryRawRetentionDays, FiveMin: s.History5mRetentionDays, Hourly: s.History1hRetentionDays}
+}
+
+func keeps(days int, window time.Duration) bool {
+	return days == 0 || time.Duration(days)*24*time.Hour >= window
+}
+
+// Resolution выбирает разрешение для периода длиной window: сырые точки до суток,
+// 5-минутные агрегаты до недели (если хранятся так долго), дальше часовые
+func Resolution(window time.Duration) string {
+	switch {
+	case window <= 24*time.Hour:
+		return Raw
+	case window <= 7*24*time.Hour && keeps(LoadRetention().FiveMin, window):
+		return models.HistoryResolution5m
+	default:
+		return models.HistoryResolution1h
+	}
+}
+
+// span — участок периода, читаемый из одного разрешения; нулевой to — до текущего момента
+type span struct {
+	res      string
+	from, to time.Time
+}
+
+// levelIndex — позиция разрешения в levels; -1 для сырых точек
+func levelIndex(res string) int {
+	for i, l := range levels {
+		if l.name == res {
+			return i
+		}
+	}
+	return -1
+}
+
+// plan разбивает период с since на участки: агрегаты разрешения res, затем более мелкие
+// агрегаты и сырые точки, которые в res ещё не свёрнуты
+func plan(serverID uint, since time.Time, res string) []span {
+	var spans []span
+	from := since
+	for i := levelIndex(res); i >= 0; i-- {
+		var last sql.NullTime
+		database.DB.Model(&models.PlayerHistoryRollup{}).
+			Select("MAX(bucket_start)").
+			Where("server_id = ? AND resolution = ? AND bucket_start >= ?", serverID, levels[i].name, from).
+			Row().Scan(&last) //nolint:errcheck
+		if !last.Valid {
+			continue
+		}
+		to := last.Time.Add(levels[i].step)
+		spans = append(spans, span{res: levels[i].name, from: from, to: to})
+		from = to
+	}
+	return append(spans, span{res: Raw, from: from})
+}
+
+// Points возвращает историю сервера с since в разрешении res. Агрегаты отдаются в форме
+// PlayerHistory: Count — средний онлайн за интервал, IsOnline — сервер отвечал в большинстве
+// опросов, Timestamp — начало интервала.
+func Points(serverID uint, since time.Time, res string) []models.PlayerHistory {
+	points := []models.PlayerHistory{}
+	for _, sp := range plan(serverID, since, res) {
+		if sp.res == Raw {
+			var raw []models.PlayerHistory
+			database.DB.
+				Where("server_id = ? AND timestamp >= ?", serverID, sp.from).
+				Order("timestamp ASC").
+				Find(&raw)
+			points = append(points, raw...)
+			continue
+		}
+		var rows []models.PlayerHistoryRollup
+		database.DB.
+			Where("server_id = ? AND resolution = ? AND bucket_start >= ? AND bucket_start < ?", serverID, sp.res, sp.from, sp.to).
+			Order("bucket_start ASC").
+			Find(&rows)
+		for _, r := range rows {
+			points = append(points, models.PlayerHistory{
+				ServerID:  r.ServerID,
+				Count:     int(math.Round(r.AvgPlayers)),
+				IsOnline:  r.OnlineSamples*2 >= r.Samples,
+				PingMS:    int(math.Round(r.AvgPing)),
+				Timestamp: r.BucketStart,
+			})
+		}
+	}
+	return points
+}
+
+// Summary — сводка по истории сервера за период
+type Summary struct {
+	Samples       int64   // опросов всего
+	OnlineSamples int64   // из них сервер отвечал
+	Peak          int     // максимум игроков
+	AvgOnline     float64 // средний онлайн, пока сервер отвечал
+}
+
+// Uptime — доля успешных опросов, проценты
+func (s Summary) Uptime() float64 {
+	if s.Samples == 0 {
+		return 0
+	}
+	return float64(s.OnlineSamples) / float64(s.Samples) * 100
+}
+
+// Summarize считает сводку по истории сервера с since в разрешении res
+func Summarize(serverID uint, since time.Time, res string) Summary {
+	var sum Summary
+	var players float64 // сумма игроков по онлайн-опросам
+	for _, sp := range plan(serverID, since, res) {
+		var row struct {
+			Samples       int64
+			OnlineSamples int64
+			Peak          int
+			Players       float64
+		}
+		if sp.res == Raw {
+			database.DB.Model(&models.PlayerHistory{}).
+				Select(`COUNT(*) AS samples,
+					COALESCE(SUM(is_online), 0) AS online_samples,
+					COALESCE(MAX(CASE WHEN is_online THEN count END), 0) AS peak,
+					COALESCE(SUM(CASE WHEN is_online THEN count END), 0) AS players`).
+				Where("server_id = ? AND timestamp >= ?", serverID, sp.from).
+				Scan(&row)
+		} else {
+			// Офлайн-опросы пишутся с нулём игроков, поэтому avg_players*samples — сумма по онлайн-опросам
+			database.DB.Model(&models.PlayerHistoryRollup{}).
+				Select(`COALESCE(SUM(samples), 0) AS samples,
+					COALESCE(SUM(online_samples), 0) AS online_samples,
+					COALESCE(MAX(max_players), 0) AS peak,
+					COALESCE(SUM(avg_players * samples), 0) AS players`).
+				Where("server_id = ? AND resolution = ? AND bucket_start >= ? AND bucket_start < ?", serverID, sp.res, sp.from, sp.to).
+				Scan(&row)
+		}
+		sum.Samples += row.Samples
+		sum.OnlineSamples += row.OnlineSamples
+		players += row.Players
+		if row.Peak > sum.Peak {
+			sum.Peak = row.Peak
+		}
+	}
+	if sum.OnlineSamples > 0 {
+		sum.AvgOnline = players / float64(sum.OnlineSamples)
+	}
+	return sum
+}
@@ -0,0 +1,184 @@
+package history
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+const (
+	// rollupLag — сырые точки пишутся пачками, поэтому интервал сворачивается
+	// не раньше чем через rollupLag после его конца
+	rollupLag = time.Minute
+	// rollupChunk — сколько истории сворачивается одним запросом при догонке
+	rollupChunk = 24 * time.Hour
+	// purgeBatch — строк за один DELETE, чтобы не держать долгие блокировки
+	purgeBatch = 10000
+)
+
+// rollupSQL — запросы свёртки: 5-минутные агрегаты из сырых точек, часовые — из 5-минутных.
+// Интервалы считаются по местному времени БД, как и bucketStart.
+var rollupSQL = map[string]string{
+	models.HistoryResolution5m: `
+		INSERT INTO player_history_rollups
+			(server_id, resolution, bucket_start, samples, online_samples, min_players, max_players, avg_players, avg_ping)
+		SELECT server_id, '5m',
+			DATE_FORMAT(timestamp, '%Y-%m-%d %H:00:00') + INTERVAL (MINUTE(timestamp) DIV 5 * 5) MINUTE AS bucket,
+			COUNT(*), SUM(is_online), MIN(count), MAX(count), AVG(count),
+			COALESCE(AVG(CASE WHEN is_online THEN ping_ms END), 0)
+		FROM player_histories
+		WHERE timestamp >= ? AND timestamp < ?
+		GROUP BY server_id, bucket` + rollupUpsert,
+	models.HistoryResolution1h: `
+		INSERT INTO player_history_rollups
+			(server_id, resolution, bucket_start, samples, online_samples, min_players, max_players, avg_players, avg_ping)
+		SELECT server_id, '1h',
+			DATE_FORMAT(bucket_start, '%Y-%m-%d %H:00:00') AS bucket,
+			SUM(samples), SUM(online_samples), MIN(min_players), MAX(max_players),
+			SUM(avg_players * samples) / SUM(samples),
+			COALESCE(SUM(avg_ping * online_samples) / NULLIF(SUM(online_samples), 0), 0)
+		FROM player_history_rollups
+		WHERE resolution = '5m' AND bucket_start >= ? AND bucket_start < ?
+		GROUP BY server_id, bucket` + rollupUpsert,
+}
+
+const rollupUpsert = `
+		ON DUPLICATE KEY UPDATE
+			samples = VALUES(samples), online_samples = VALUES(online_samples),
+			min_players = VALUES(min_players), max_players = VALUES(max_players),
+			avg_players = VALUES(avg_players), avg_ping = VALUES(avg_ping)`
+
+// bucketStart — начало интервала длиной step (5 минут или час), в который попадает t
+func bucketStart(t time.Time, step time.Duration) time.Time {
+	m := t.Minute()
+	m -= m % int(step/time.Minute)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), m, 0, 0, t.Location())
+}
+
+// lastBucket — начало последнего свёрнутого интервала разрешения res
+func lastBucket(res string) (time.Time, bool) {
+	var last sql.NullTime
+	database.DB.Model(&models.PlayerHistoryRollup{}).
+		Select("MAX(bucket_start)").
+		Where("resolution = ?", res).
+		Row().Scan(&last) //nolint:errcheck
+	return last.Time, last.Valid
+}
+
+// rollupStart — откуда продолжать свёртку: с последнего свёрнутого интервала (он пересчитывается,
+// чтобы учесть поздно записанные точки) или с начала исходных данных при первом запуске
+func rollupStart(i int) (time.Time, bool) {
+	if last, ok := lastBucket(levels[i].name); ok {
+		return last, true
+	}
+	var first sql.NullTime
+	if i == 0 {
+		database.DB.Model(&models.PlayerHistory{}).Select("MIN(timestamp)").Row().Scan(&first) //nolint:errcheck
+	} else {
+		database.DB.Model(&models.PlayerHistoryRollup{}).
+			Select("MIN(bucket_start)").
+			Where("resolution = ?", levels[i-1].name).
+			Row().Scan(&first) //nolint:errcheck
+	}
+	if !first.Valid {
+		return time.Time{}, false
+	}
+	return bucketStart(first.Time, levels[i].step), true
+}
+
+// Rollup сворачивает накопившуюся историю во все разрешения. При первом запуске догоняет
+// всю существующую историю частями по rollupChunk; прерывается при закрытии done.
+func Rollup(done <-chan struct{}) error {
+	end := time.Now().Add(-rollupLag)
+	for i, l := range levels {
+		start, ok := rollupStart(i)
+		if !ok {
+			continue
+		}
+		// Интервал сворачивается только целиком: для часовых — когда закрыты все его 5-минутные
+		levelEnd := bucketStart(end, l.step)
+		end = levelEnd
+		for start.Before(levelEnd) {
+			if stopped(done) {
+				return nil
+			}
+			chunkEnd := start.Add(rollupChunk)
+			if chunkEnd.After(levelEnd) {
+				chunkEnd = levelEnd
+			}
+			if err := database.DB.Exec(rollupSQL[l.name], start, chunkEnd).Error; err != nil {
+				return err
+			}
+			start = chunkEnd
+		}
+	}
+	return nil
+}
+
+// ApplyRetention удаляет историю старше сроков хранения. Сырые точки и 5-минутные агрегаты
+// удаляются только после того, как свёрнуты в следующее разрешение.
+func ApplyRetention(done <-chan struct{}) {
+	r := LoadRetention()
+	now := time.Now()
+
+	if r.Raw > 0 {
+		if cutoff, ok := retentionCutoff(now, r.Raw, levels[0].name); ok {
+			purge(done, "raw history points", "DELETE FROM player_histories WHERE timestamp < ?", cutoff)
+		}
+	}
+	if r.FiveMin > 0 {
+		if cutoff, ok := retentionCutoff(now, r.FiveMin, levels[1].name); ok {
+			purge(done, "5m history rollups",
+				"DELETE FROM player_history_rollups WHERE resolution = ? AND bucket_start < ?", levels[0].name, cutoff)
+		}
+	}
+	if r.Hourly > 0 {
+		purge(done, "1h history rollups",
+			"DELETE FROM player_history_rollups WHERE resolution = ? AND bucket_start < ?", levels[1].name, now.AddDate(0, 0, -r.Hourly))
+	}
+}
+
+// retentionCutoff — граница удаления: срок хранения, но не позже последнего интервала,
+// уже свёрнутого в разрешение next
+func retentionCutoff(now time.Time, days int, next string) (time.Time, bool) {
+	last, ok := lastBucket(next)
+	if !ok {
+		return time.Time{}, false
+	}
+	cutoff := now.AddDate(0, 0, -days)
+	if last.Before(cutoff) {
+		cutoff = last
+	}
+	return cutoff, true
+}
+
+// purge удаляет строки пачками по purgeBatch
+func purge(done <-chan struct{}, what, query string, args ...interface{}) {
+	var total int64
+	for !stopped(done) {
+		res := database.DB.Exec(query+" LIMIT ?", append(args, purgeBatch)...)
+		if res.Error != nil {
+			log.Printf("[history] purge %s: %v", what, res.Error)
+			break
+		}
+		total += res.RowsAffected
+		if res.RowsAffected < purgeBatch {
+			break
+		}
+	}
+	if total > 0 {
+		log.Printf("[history] purged %d %s past retention", total, what)
+	}
+}
+
+func stopped(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,52 @@
+package history
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestBucketStart(t *testing.T) {
+	// Asia/Kolkata — смещение +05:30: интервалы считаются по местному времени, а не по UTC
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(h, m, s int) time.Time { return time.Date(2026, 3, 1, h, m, s, 0, loc) }
+
+	tests := []struct {
+		t    time.Time
+		step time.Duration
+		want time.Time
+	}{
+		{at(10, 0, 0), 5 * time.Minute, at(10, 0, 0)},
+		{at(10, 4, 59), 5 * time.Minute, at(10, 0, 0)},
+		{at(10, 5, 0), 5 * time.Minute, at(10, 5, 0)},
+		{at(23, 59, 59), 5 * time.Minute, at(23, 55, 0)},
+		{at(10, 0, 0), time.Hour, at(10, 0, 0)},
+		{at(10, 59, 59), time.Hour, at(10, 0, 0)},
+	}
+	for _, tt := range tests {
+		if got := bucketStart(tt.t, tt.step); !got.Equal(tt.want) {
+			t.Errorf("bucketStart(%v, %v) = %v, want %v", tt.t, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestKeeps(t *testing.T) {
+	week := 7 * 24 * time.Hour
+	tests := []struct {
+		days int
+		want bool
+	}{
+		{0, true}, // бессрочно
+		{7, true},
+		{6, false},
+		{30, true},
+	}
+	for _, tt := range tests {
+		if got := keeps(tt.days, week); got != tt.want {
+			t.Errorf("keeps(%d, week) = %v, want %v", tt.days, got, tt.want)
+		}
+	}
+}
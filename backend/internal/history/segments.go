@@ -0,0 +1,87 @@
+package history
+
+import (
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// Segment — отрезок, в течение которого сервер наблюдался; Up — доля отрезка, когда он отвечал
+type Segment struct {
+	From, To time.Time
+	Up       float64
+}
+
+// Segments возвращает покрытие периода [from, to) наблюдениями в разрешении res.
+// Сырая точка покрывает время до следующей, но не дольше maxGap: более длинный пропуск
+// (поллер не работал) в покрытие не входит. Агрегат покрывает свой интервал целиком,
+// Up для него — доля успешных опросов внутри интервала.
+func Segments(serverID uint, from, to time.Time, res string, maxGap time.Duration) []Segment {
+	var segs []Segment
+	for _, sp := range plan(serverID, from, res) {
+		if !sp.from.Before(to) {
+			break
+		}
+		if sp.res == Raw {
+			var raw []rawPoint
+			database.DB.Model(&models.PlayerHistory{}).
+				Select("timestamp, is_online").
+				Where("server_id = ? AND timestamp >= ? AND timestamp < ?", serverID, sp.from, to).
+				Order("timestamp ASC").
+				Scan(&raw)
+			segs = append(segs, rawSegments(raw, to, maxGap)...)
+			continue
+		}
+
+		step := levels[levelIndex(sp.res)].step
+		until := sp.to
+		if to.Before(until) {
+			until = to
+		}
+		var rows []models.PlayerHistoryRollup
+		database.DB.
+			Select("bucket_start, samples, online_samples").
+			Where("server_id = ? AND resolution = ? AND bucket_start >= ? AND bucket_start < ?", serverID, sp.res, sp.from, until).
+			Order("bucket_start ASC").
+			Find(&rows)
+		for _, r := range rows {
+			if r.Samples == 0 {
+				continue
+			}
+			end := r.BucketStart.Add(step)
+			if end.After(to) {
+				end = to
+			}
+			segs = append(segs, Segment{From: r.BucketStart, To: end, Up: float64(r.OnlineSamples) / float64(r.Samples)})
+		}
+	}
+	return segs
+}
+
+// rawPoint — сырая точка истории, достаточная для покрытия
+type rawPoint struct {
+	Timestamp time.Time
+	IsOnline  bool
+}
+
+// rawSegments строит покрытие по сырым точкам, упорядоченным по времени: точка покрывает
+// время до следующей, но не дольше maxGap и не дальше to
+func rawSegments(raw []rawPoint, to time.Time, maxGap time.Duration) []Segment {
+	segs := make([]Segment, 0, len(raw))
+	for i, p := range raw {
+		end := p.Timestamp.Add(maxGap)
+		if i+1 < len(raw) && raw[i+1].Timestamp.Before(end) {
+			end = raw[i+1].Timestamp
+		}
+		if end.After(to) {
+			end = to
+		}
+		seg := Segment{From: p.Timestamp, To: end}
+		if p.IsOnline {
+			seg.Up = 1
+		}
+		segs = append(segs, seg)
+	}
+	return segs
+}
@@ -0,0 +1,36 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRawSegments(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+
+	raw := []rawPoint{
+		{Timestamp: at(0), IsOnline: true},
+		{Timestamp: at(1), IsOnline: false},
+		{Timestamp: at(2), IsOnline: true},
+		// поллер не работал 20 минут: точка покрывает только maxGap
+		{Timestamp: at(22), IsOnline: true},
+		{Timestamp: at(29), IsOnline: false}, // покрытие обрезается концом периода
+	}
+	got := rawSegments(raw, at(30), 5*time.Minute)
+	want := []Segment{
+		{From: at(0), To: at(1), Up: 1},
+		{From: at(1), To: at(2), Up: 0},
+		{From: at(2), To: at(7), Up: 1},
+		{From: at(22), To: at(27), Up: 1},
+		{From: at(29), To: at(30), Up: 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("rawSegments() = %v, want %v", got, want)
+	}
+
+	if got := rawSegments(nil, at(30), 5*time.Minute); len(got) != 0 {
+		t.Errorf("rawSegments(nil) = %v, want none", got)
+	}
+}
@@ -0,0 +1,137 @@
+// Package leader выбирает одну реплику бэкенда, которая опрашивает серверы и запускает ботов.
+// Лидерство — строка в таблице leader_leases с временем истечения: лидер продлевает её,
+// остальные реплики забирают аренду, когда та истекла. Время берётся из БД (NOW()),
+// поэтому расхождение часов между репликами не важно.
+package leader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+const (
+	// leaseTTL — через сколько без продления аренда считается брошенной
+	leaseTTL = 15 * time.Second
+	// renewInterval — как часто лидер продлевает аренду и последователи пытаются её взять
+	renewInterval = 5 * time.Second
+)
+
+// Elector — участник выборов за одну именованную аренду
+type Elector struct {
+	name string
+	id   string
+}
+
+// New создаёт участника выборов с уникальным для процесса идентификатором
+func New(name string) *Elector {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	rand.Read(b) //nolint:errcheck
+	return &Elector{name: name, id: fmt.Sprintf("%s/%d/%s", host, os.Getpid(), hex.EncodeToString(b))}
+}
+
+// ID — идентификатор этой реплики в таблице аренды
+func (e *Elector) ID() string { return e.id }
+
+// Run участвует в выборах до отмены ctx. Пока реплика лидер, работает lead,
+// иначе — follow (может быть nil). Оба получают контекст, отменяемый при смене роли;
+// Run дожидается их завершения. При выходе аренда освобождается, чтобы другая реплика
+// не ждала leaseTTL.
+func (e *Elector) Run(ctx context.Context, lead, follow func(ctx context.Context)) {
+	e.ensureRow()
+	ticker := time.NewTicker(renewInterval)
+	defer ticker.Stop()
+
+	var (
+		leading bool
+		stop    context.CancelFunc
+		done    chan struct{}
+	)
+	start := func(fn func(context.Context)) {
+		if fn == nil {
+			return
+		}
+		var roleCtx context.Context
+		roleCtx, stop = context.WithCancel(ctx)
+		done = make(chan struct{})
+		go func() {
+			defer close(done)
+			fn(roleCtx)
+		}()
+	}
+	end := func() {
+		if stop != nil {
+			stop()
+			<-done
+			stop, done = nil, nil
+		}
+	}
+
+	start(follow)
+	for {
+		held := e.tryAcquire()
+		switch {
+		case held && !leading:
+			log.Printf("[leader] %s: acquired lease %q", e.id, e.name)
+			end()
+			leading = true
+			start(lead)
+		case !held && leading:
+			log.Printf("[leader] %s: lost lease %q", e.id, e.name)
+			end()
+			leading = false
+			start(follow)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			end()
+			if leading {
+				e.release()
+			}
+			return
+		}
+	}
+}
+
+// ensureRow создаёт строку аренды, если её ещё нет
+func (e *Elector) ensureRow() {
+	database.DB.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.LeaderLease{Name: e.name, ExpiresAt: time.Unix(0, 0).UTC(), RenewedAt: time.Now()})
+}
+
+// tryAcquire продлевает свою аренду или забирает истёкшую. Ошибка БД означает потерю лидерства:
+// лучше кратко остаться без поллера, чем опрашивать вдвоём.
+func (e *Elector) tryAcquire() bool {
+	res := database.DB.Model(&models.LeaderLease{}).
+		Where("name = ? AND (holder_id = ? OR expires_at < NOW())", e.name, e.id).
+		Updates(map[string]interface{}{
+			"holder_id":  e.id,
+			"expires_at": gorm.Expr("DATE_ADD(NOW(), INTERVAL ? SECOND)", int(leaseTTL.Seconds())),
+			"renewed_at": gorm.Expr("NOW()"),
+		})
+	if res.Error != nil {
+		log.Printf("[leader] renew %q: %v", e.name, res.Error)
+		return false
+	}
+	return res.RowsAffected == 1
+}
+
+func (e *Elector) release() {
+	database.DB.Model(&models.LeaderLease{}).
+		Where("name = ? AND holder_id = ?", e.name, e.id).
+		Updates(map[string]interface{}{"holder_id": "", "expires_at": gorm.Expr("NOW()")})
+	log.Printf("[leader] %s: released lease %q", e.id, e.name)
+}
@@ -0,0 +1,122 @@
+// Package maintenance разворачивает окна плановых работ (models.MaintenanceWindow) во вхождения
+// и отвечает, идут ли на сервере работы. Повторяющиеся окна считаются по местному времени:
+// ежедневный перезапуск в 06:00 остаётся в 06:00 и после перехода на летнее время.
+package maintenance
+
+import (
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// Occurrence — одно вхождение окна работ [From, To)
+type Occurrence struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// periodDays — шаг повторения окна в днях; 0 — окно разовое
+func periodDays(w *models.MaintenanceWindow) int {
+	switch w.Recurrence {
+	case models.MaintenanceDaily:
+		return 1
+	case models.MaintenanceWeekly:
+		return 7
+	}
+	return 0
+}
+
+// Occurrences возвращает вхождения окна w, пересекающиеся с [from, to)
+func Occurrences(w *models.MaintenanceWindow, from, to time.Time) []Occurrence {
+	dur := time.Duration(w.DurationMin) * time.Minute
+	start := w.StartsAt.Local()
+	days := periodDays(w)
+	if days == 0 {
+		if start.Before(to) && start.Add(dur).After(from) {
+			return []Occurrence{{From: start, To: start.Add(dur)}}
+		}
+		return nil
+	}
+
+	// Первое вхождение, которое может задеть from; на шаг раньше — с запасом на смену часового пояса
+	k := 0
+	if lead := from.Sub(start.Add(dur)); lead > 0 {
+		k = int(lead/(time.Duration(days)*24*time.Hour)) - 1
+		if k < 0 {
+			k = 0
+		}
+	}
+	var out []Occurrence
+	for ; ; k++ {
+		s := start.AddDate(0, 0, k*days)
+		if !s.Before(to) {
+			break
+		}
+		if e := s.Add(dur); e.After(from) {
+			out = append(out, Occurrence{From: s, To: e})
+		}
+	}
+	return out
+}
+
+// Current — вхождение окна w, идущее в момент at
+func Current(w *models.MaintenanceWindow, at time.Time) (Occurrence, bool) {
+	occ := Occurrences(w, at, at.Add(time.Nanosecond))
+	if len(occ) == 0 {
+		return Occurrence{}, false
+	}
+	return occ[0], true
+}
+
+// Next — ближайшее вхождение окна w, которое ещё не закончилось к моменту at
+func Next(w *models.MaintenanceWindow, at time.Time) (Occurrence, bool) {
+	days := periodDays(w)
+	if days == 0 {
+		start := w.StartsAt.Local()
+		o := Occurrence{From: start, To: start.Add(time.Duration(w.DurationMin) * time.Minute)}
+		return o, o.To.After(at)
+	}
+	// Час запаса — на случай, если период удлинился из-за перевода часов
+	occ := Occurrences(w, at, at.Add(time.Duration(days)*24*time.Hour+time.Hour))
+	if len(occ) == 0 {
+		return Occurrence{}, false
+	}
+	return occ[0], true
+}
+
+// Active — идёт ли на сервере плановая работа в момент at
+func Active(serverID uint, at time.Time) bool {
+	var windows []models.MaintenanceWindow
+	database.DB.Where("server_id = ? AND starts_at <= ?", serverID, at).Find(&windows)
+	for i := range windows {
+		if _, ok := Current(&windows[i], at); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveServers — серверы, на которых в момент at идут плановые работы
+func ActiveServers(at time.Time) map[uint]bool {
+	var windows []models.MaintenanceWindow
+	database.DB.Where("starts_at <= ?", at).Find(&windows)
+	active := map[uint]bool{}
+	for i := range windows {
+		if _, ok := Current(&windows[i], at); ok {
+			active[windows[i].ServerID] = true
+		}
+	}
+	return active
+}
+
+// Between — все вхождения окон сервера, пересекающиеся с [from, to)
+func Between(serverID uint, from, to time.Time) []Occurrence {
+	var windows []models.MaintenanceWindow
+	database.DB.Where("server_id = ? AND starts_at < ?", serverID, to).Find(&windows)
+	var out []Occurrence
+	for i := range windows {
+		out = append(out, Occurrences(&windows[i], from, to)...)
+	}
+	return out
+}
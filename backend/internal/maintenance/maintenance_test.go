@@ -0,0 +1,126 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// useLocal подменяет местный часовой пояс: повторяющиеся окна считаются по нему
+func useLocal(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = prev })
+	return loc
+}
+
+func window(start time.Time, durationMin int, recurrence string) *models.MaintenanceWindow {
+	return &models.MaintenanceWindow{StartsAt: start, DurationMin: durationMin, Recurrence: recurrence}
+}
+
+func TestOccurrencesOnce(t *testing.T) {
+	loc := useLocal(t, "Europe/Berlin")
+	w := window(time.Date(2026, 5, 10, 12, 0, 0, 0, loc), 30, models.MaintenanceOnce)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     int
+	}{
+		{"covers window", time.Date(2026, 5, 10, 0, 0, 0, 0, loc), time.Date(2026, 5, 11, 0, 0, 0, 0, loc), 1},
+		{"overlaps the end", time.Date(2026, 5, 10, 12, 29, 0, 0, loc), time.Date(2026, 5, 10, 13, 0, 0, 0, loc), 1},
+		{"starts at the end", time.Date(2026, 5, 10, 12, 30, 0, 0, loc), time.Date(2026, 5, 10, 13, 0, 0, 0, loc), 0},
+		{"ends at the start", time.Date(2026, 5, 10, 11, 0, 0, 0, loc), time.Date(2026, 5, 10, 12, 0, 0, 0, loc), 0},
+	}
+	for _, tt := range tests {
+		if got := Occurrences(w, tt.from, tt.to); len(got) != tt.want {
+			t.Errorf("%s: %d occurrences, want %d", tt.name, len(got), tt.want)
+		}
+	}
+}
+
+func TestOccurrencesDailyAcrossDST(t *testing.T) {
+	// 29 марта 2026 Европа переходит на летнее время: сутки длятся 23 часа
+	loc := useLocal(t, "Europe/Berlin")
+	w := window(time.Date(2026, 3, 20, 6, 0, 0, 0, loc), 60, models.MaintenanceDaily)
+
+	got := Occurrences(w, time.Date(2026, 3, 27, 0, 0, 0, 0, loc), time.Date(2026, 4, 1, 0, 0, 0, 0, loc))
+	if len(got) != 5 {
+		t.Fatalf("%d occurrences, want 5: %v", len(got), got)
+	}
+	for i, o := range got {
+		from := o.From.In(loc)
+		if from.Day() != 27+i || from.Hour() != 6 || from.Minute() != 0 {
+			t.Errorf("occurrence %d starts at %v, want March %d 06:00", i, from, 27+i)
+		}
+		if d := o.To.Sub(o.From); d != time.Hour {
+			t.Errorf("occurrence %d lasts %v, want 1h", i, d)
+		}
+	}
+	// До перевода часов 06:00 — это 05:00 UTC, после — 04:00 UTC
+	if h := got[1].From.UTC().Hour(); h != 5 {
+		t.Errorf("March 28 occurrence at %02d:00 UTC, want 05:00", h)
+	}
+	if h := got[2].From.UTC().Hour(); h != 4 {
+		t.Errorf("March 29 occurrence at %02d:00 UTC, want 04:00", h)
+	}
+}
+
+func TestOccurrencesWeeklyFarFromStart(t *testing.T) {
+	loc := useLocal(t, "Europe/Berlin")
+	// Понедельник 03:00
+	w := window(time.Date(2025, 1, 6, 3, 0, 0, 0, loc), 120, models.MaintenanceWeekly)
+
+	got := Occurrences(w, time.Date(2026, 3, 1, 0, 0, 0, 0, loc), time.Date(2026, 3, 15, 0, 0, 0, 0, loc))
+	want := []time.Time{
+		time.Date(2026, 3, 2, 3, 0, 0, 0, loc),
+		time.Date(2026, 3, 9, 3, 0, 0, 0, loc),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("occurrences = %v, want starts %v", got, want)
+	}
+	for i := range want {
+		if !got[i].From.Equal(want[i]) {
+			t.Errorf("occurrence %d starts at %v, want %v", i, got[i].From, want[i])
+		}
+	}
+}
+
+func TestOccurrencesSpanningMidnight(t *testing.T) {
+	loc := useLocal(t, "Europe/Berlin")
+	w := window(time.Date(2026, 6, 1, 23, 30, 0, 0, loc), 90, models.MaintenanceDaily)
+
+	// Вхождение предыдущего дня ещё идёт в начале периода
+	got := Occurrences(w, time.Date(2026, 6, 10, 0, 30, 0, 0, loc), time.Date(2026, 6, 10, 12, 0, 0, 0, loc))
+	if len(got) != 1 || !got[0].From.Equal(time.Date(2026, 6, 9, 23, 30, 0, 0, loc)) {
+		t.Fatalf("occurrences = %v, want the one starting June 9 23:30", got)
+	}
+
+	if _, ok := Current(w, time.Date(2026, 6, 10, 0, 59, 0, 0, loc)); !ok {
+		t.Error("Current: window at 00:59 not active")
+	}
+	if _, ok := Current(w, time.Date(2026, 6, 10, 1, 0, 0, 0, loc)); ok {
+		t.Error("Current: window at 01:00 still active")
+	}
+	next, ok := Next(w, time.Date(2026, 6, 10, 1, 0, 0, 0, loc))
+	if !ok || !next.From.Equal(time.Date(2026, 6, 10, 23, 30, 0, 0, loc)) {
+		t.Errorf("Next = %v, %v; want June 10 23:30", next, ok)
+	}
+}
+
+func TestOccurrencesBeforeFirstStart(t *testing.T) {
+	loc := useLocal(t, "Europe/Berlin")
+	w := window(time.Date(2026, 6, 1, 6, 0, 0, 0, loc), 60, models.MaintenanceDaily)
+
+	got := Occurrences(w, time.Date(2026, 5, 25, 0, 0, 0, 0, loc), time.Date(2026, 6, 2, 0, 0, 0, 0, loc))
+	if len(got) != 1 || !got[0].From.Equal(w.StartsAt) {
+		t.Fatalf("occurrences = %v, want only the first one", got)
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -36,15 +37,45 @@ type Server struct {
 	Port        uint16    `gorm:"not null"                              json:"port"`
 	GameType    string    `gorm:"type:varchar(30);not null"             json:"game_type"`
 	SecretRCON  string    `gorm:"type:varchar(255)"                     json:"-"`
+	RCONPort    uint16    `gorm:"default:0"                             json:"rcon_port"` // 0 = порт по умолчанию для протокола
+	QueryPort   uint16    `gorm:"default:0"                             json:"query_port"` // 0 = опрос по игровому Port
 	CountryCode string    `gorm:"type:varchar(2)"                       json:"country_code"`
 	CountryName string    `gorm:"type:varchar(100)"                     json:"country_name"`
 	OwnerID      uint      `gorm:"index"                                 json:"owner_id"`
 	DiscordColor string    `gorm:"type:varchar(7)"                       json:"discord_color"`
+	TrackedRules string    `gorm:"type:varchar(1000)"                    json:"tracked_rules"` // через запятую: "wipe,mapsize" — какие A2S_RULES сохранять
+	// Свой интервал опроса в секундах (0 — по умолчанию: 10 сек с игроками, 60 сек без)
+	PollInterval      int `gorm:"default:0" json:"poll_interval"`
+	EmptyPollInterval int `gorm:"default:0" json:"empty_poll_interval"`
 	CreatedAt    time.Time `                                              json:"created_at"`
 	UpdatedAt    time.Time `                                              json:"updated_at"`
 
 	Status      *ServerStatus `gorm:"foreignKey:ServerID" json:"status,omitempty"`
 	AlertConfig *AlertsConfig `gorm:"foreignKey:ServerID" json:"alert_config,omitempty"`
+	Rules       []ServerRule  `gorm:"foreignKey:ServerID" json:"rules,omitempty"`
+
+	// InMaintenance — сейчас идут плановые работы (заполняется API, в БД не хранится)
+	InMaintenance bool `gorm:"-" json:"in_maintenance"`
+}
+
+// QueryAddrPort возвращает порт для опроса: QueryPort, если задан, иначе игровой Port.
+// Valheim, ARK, Arma 3 и др. отвечают на запросы на отдельном порту (часто Port+1).
+func (s *Server) QueryAddrPort() uint16 {
+	if s.QueryPort != 0 {
+		return s.QueryPort
+	}
+	return s.Port
+}
+
+// TrackedRuleKeys возвращает список отслеживаемых правил из TrackedRules
+func (s *Server) TrackedRuleKeys() []string {
+	var keys []string
+	for _, k := range strings.Split(s.TrackedRules, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
 }
 
 // NewsTag — тег с необязательной иконкой (base64 data URL)
@@ -90,11 +121,91 @@ type ServerStatus struct {
 	ServerName   string    `gorm:"type:varchar(255)"        json:"server_name"`
 	PingMS       int       `gorm:"default:0"                json:"ping_ms"`
 	LastUpdate   time.Time `                                json:"last_update"`
+
+	// Расширенные данные A2S_INFO (пусто для протоколов, которые их не сообщают)
+	Bots             int        `gorm:"default:0"           json:"bots"`
+	ServerType       string     `gorm:"type:varchar(1)"     json:"server_type"` // d — dedicated, l — listen, p — SourceTV
+	Environment      string     `gorm:"type:varchar(1)"     json:"environment"` // l — Linux, w — Windows, m/o — macOS
+	Password         bool       `gorm:"default:false"       json:"password"`
+	VAC              bool       `gorm:"default:false"       json:"vac"`
+	Version          string     `gorm:"type:varchar(64)"    json:"version"`
+	GamePort         uint16     `gorm:"default:0"           json:"game_port"`
+	SteamID          string     `gorm:"type:varchar(20)"    json:"steam_id"` // строкой: uint64 теряет точность в JS
+	Keywords         string     `gorm:"type:varchar(512)"   json:"keywords"`
+	AppID            uint64     `gorm:"default:0"           json:"app_id"`
+	VersionChangedAt *time.Time `                           json:"version_changed_at"`
+
+	// MOTDSpans — JSON-массив MOTDSpan с цветами и стилями (Minecraft)
+	MOTDSpans string `gorm:"type:text" json:"motd_spans,omitempty"`
+	// Favicon — data URL иконки из ответа опроса; хранится отдельно в ServerFavicon
+	Favicon string `gorm:"-" json:"-"`
+
+	// Серия неудачных опросов: счётчик подряд и причина последней (timeout, refused, unreachable, dns, parse)
+	FailCount     int    `gorm:"default:0"         json:"fail_count"`
+	FailureReason string `gorm:"type:varchar(16)"  json:"failure_reason,omitempty"`
+}
+
+// MOTDSpan — фрагмент MOTD с единым оформлением.
+// Color — имя цвета Minecraft ("gold", "dark_aqua") или "#RRGGBB".
+type MOTDSpan struct {
+	Text          string `json:"text"`
+	Color         string `json:"color,omitempty"`
+	Bold          bool   `json:"bold,omitempty"`
+	Italic        bool   `json:"italic,omitempty"`
+	Underlined    bool   `json:"underlined,omitempty"`
+	Strikethrough bool   `json:"strikethrough,omitempty"`
+	Obfuscated    bool   `json:"obfuscated,omitempty"`
+}
+
+// ServerFavicon — иконка сервера из ответа опроса (Minecraft favicon), отдаётся публичным эндпоинтом
+type ServerFavicon struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ServerID  uint      `gorm:"uniqueIndex;not null"     json:"server_id"`
+	Data      string    `gorm:"type:mediumtext"          json:"-"` // base64 data URL
+	Hash      string    `gorm:"type:varchar(64)"         json:"hash"`
+	UpdatedAt time.Time `                                json:"updated_at"`
+}
+
+// LeaderLease — аренда роли лидера между репликами бэкенда (см. пакет leader)
+type LeaderLease struct {
+	Name      string    `gorm:"type:varchar(64);primaryKey" json:"name"`
+	HolderID  string    `gorm:"type:varchar(128)"           json:"holder_id"`
+	ExpiresAt time.Time `                                   json:"expires_at"`
+	RenewedAt time.Time `                                   json:"renewed_at"`
+}
+
+// PollAgent — удалённый агент опроса: тот же бинарник в режиме агента, запущенный в другом регионе
+type PollAgent struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement"             json:"id"`
+	Name       string     `gorm:"type:varchar(64);not null"            json:"name"`
+	Region     string     `gorm:"type:varchar(32);index;not null"      json:"region"`
+	Token      string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Enabled    bool       `gorm:"default:true"                         json:"enabled"`
+	LastSeenAt *time.Time `                                            json:"last_seen_at"`
+	LastIP     string     `gorm:"type:varchar(45)"                     json:"last_ip"`
+	CreatedAt  time.Time  `                                            json:"created_at"`
+}
+
+// RegionStatus — статус сервера с точки зрения одного агента
+type RegionStatus struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement"               json:"id"`
+	ServerID      uint      `gorm:"uniqueIndex:idx_region_srv_agent;not null" json:"server_id"`
+	AgentID       uint      `gorm:"uniqueIndex:idx_region_srv_agent;not null" json:"agent_id"`
+	Region        string    `gorm:"type:varchar(32)"                       json:"region"`
+	OnlineStatus  bool      `gorm:"default:false"                          json:"online_status"`
+	PlayersNow    int       `gorm:"default:0"                              json:"players_now"`
+	PingMS        int       `gorm:"default:0"                              json:"ping_ms"`
+	FailureReason string    `gorm:"type:varchar(16)"                       json:"failure_reason,omitempty"`
+	CheckedAt     time.Time `                                              json:"checked_at"`
 }
 
 // ServerPlayer — игрок на сервере (не хранится в БД, только для API ответа)
 type ServerPlayer struct {
-	Name string `json:"name"`
+	Name        string   `json:"name"`
+	Score       int      `json:"score,omitempty"`
+	Ping        int      `json:"ping,omitempty"`
+	Duration    int      `json:"duration,omitempty"`    // секунды на сервере по данным самого сервера
+	Identifiers []string `json:"identifiers,omitempty"` // FiveM: license:, steam:, discord:, …
 }
 
 // PlayerHistory — история онлайна для графиков
@@ -107,6 +218,61 @@ type PlayerHistory struct {
 	Timestamp time.Time `gorm:"index;not null"           json:"timestamp"`
 }
 
+// Разрешения агрегатов истории (PlayerHistoryRollup.Resolution)
+const (
+	HistoryResolution5m = "5m"
+	HistoryResolution1h = "1h"
+)
+
+// PlayerHistoryRollup — агрегат PlayerHistory за 5 минут или час для длинных периодов.
+// Доля онлайна — OnlineSamples / Samples; AvgPing считается только по онлайн-опросам.
+type PlayerHistoryRollup struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement"                                                    json:"id"`
+	ServerID      uint      `gorm:"uniqueIndex:idx_rollup_bucket;not null"                                      json:"server_id"`
+	Resolution    string    `gorm:"uniqueIndex:idx_rollup_bucket;index:idx_rollup_age;type:varchar(8);not null" json:"resolution"`
+	BucketStart   time.Time `gorm:"uniqueIndex:idx_rollup_bucket;index:idx_rollup_age;not null"                 json:"bucket_start"`
+	Samples       int       `gorm:"not null"                                                                    json:"samples"`
+	OnlineSamples int       `gorm:"not null"                                                                    json:"online_samples"`
+	MinPlayers    int       `gorm:"not null"                                                                    json:"min_players"`
+	MaxPlayers    int       `gorm:"not null"                                                                    json:"max_players"`
+	AvgPlayers    float64   `gorm:"not null"                                                                    json:"avg_players"`
+	AvgPing       float64   `gorm:"not null"                                                                    json:"avg_ping"`
+}
+
+// ServerIncident — простой сервера: от подтверждённого перехода в офлайн до восстановления
+type ServerIncident struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement"                 json:"id"`
+	ServerID    uint       `gorm:"index:idx_incident_server;not null"       json:"server_id"`
+	StartedAt   time.Time  `gorm:"index:idx_incident_server;index;not null" json:"started_at"` // первый неудачный опрос
+	ResolvedAt  *time.Time `gorm:"index"                                    json:"resolved_at"` // nil — сервер ещё недоступен
+	DurationSec int        `gorm:"default:0"                                json:"duration_sec"`
+	Cause       string     `gorm:"type:varchar(16)"                         json:"cause"` // FailureReason последнего опроса перед офлайном
+	AckUserID   *uint      `                                                json:"ack_user_id"`
+	AckUsername string     `gorm:"type:varchar(100)"                        json:"ack_username"`
+	AckAt       *time.Time `                                                json:"ack_at"`
+	AckNote     string     `gorm:"type:varchar(500)"                        json:"ack_note"`
+}
+
+// Повторение окна плановых работ
+const (
+	MaintenanceOnce   = ""       // разовое
+	MaintenanceDaily  = "daily"  // каждый день в то же время
+	MaintenanceWeekly = "weekly" // каждую неделю в тот же день и время
+)
+
+// MaintenanceWindow — плановые работы на сервере. Во время окна оповещения об офлайне
+// не отправляются, статус показывается как «обслуживание», а простой не входит в аптайм.
+type MaintenanceWindow struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement"             json:"id"`
+	ServerID    uint      `gorm:"index;not null"                       json:"server_id"`
+	Title       string    `gorm:"type:varchar(200)"                    json:"title"`
+	StartsAt    time.Time `gorm:"not null"                             json:"starts_at"` // для повторяющихся — первое вхождение
+	DurationMin int       `gorm:"not null"                             json:"duration_min"`
+	Recurrence  string    `gorm:"type:varchar(16);not null;default:''" json:"recurrence"` // "", daily, weekly
+	CreatedBy   uint      `                                            json:"created_by"`
+	CreatedAt   time.Time `                                            json:"created_at"`
+}
+
 // PlayerSession — сессия игрока на сервере (от входа до выхода)
 type PlayerSession struct {
 	ID         uint       `gorm:"primaryKey;autoIncrement"                     json:"id"`
@@ -152,6 +318,9 @@ type SiteSettings struct {
 	VRisingFreePlotIcon     string `gorm:"type:longtext"                          json:"-"`                         // base64 кастомной иконки свободного участка
 	VRisingHideAdmins       bool   `gorm:"default:false"                          json:"vrising_hide_admins"`       // скрывать администраторов с живой карты
 	MaintenanceMode         bool   `gorm:"default:false"                          json:"maintenance_mode"`          // режим обслуживания — закрывает сайт для не-админов
+	HistoryRawRetentionDays int    `gorm:"default:3"                              json:"history_raw_retention_days"` // срок хранения сырых точек истории, дни (0 — бессрочно)
+	History5mRetentionDays  int    `gorm:"default:30"                             json:"history_5m_retention_days"`  // срок хранения 5-минутных агрегатов
+	History1hRetentionDays  int    `gorm:"default:365"                            json:"history_1h_retention_days"`  // срок хранения часовых агрегатов
 }
 
 // DiscordEmbed — хранит активные embed-сообщения бота для восстановления после перезапуска.
@@ -178,6 +347,7 @@ type EmbedFieldConfig struct {
 	Average24h  bool `json:"average_24h"`
 	UniqueToday bool `json:"unique_today"`
 	PlayerList  bool `json:"player_list"`
+	Rules       bool `json:"rules"`
 }
 
 // DefaultEmbedFieldConfig returns a config with all fields enabled.
@@ -186,6 +356,7 @@ func DefaultEmbedFieldConfig() EmbedFieldConfig {
 		Status: true, Address: true, Country: true, Game: true,
 		Map: true, Ping: true, Players: true, Peak24h: true,
 		Uptime24h: true, Average24h: true, UniqueToday: true, PlayerList: true,
+		Rules: true,
 	}
 }
 
@@ -298,6 +469,24 @@ type AuditLog struct {
 	CreatedAt  time.Time `gorm:"index"                   json:"created_at"`
 }
 
+// RCONAccess — делегированный доступ пользователя к RCON-консоли чужого сервера
+type RCONAccess struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"                      json:"id"`
+	ServerID  uint      `gorm:"uniqueIndex:idx_rcon_access_srv_user;not null" json:"server_id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_rcon_access_srv_user;index;not null" json:"user_id"`
+	GrantedBy uint      `                                                     json:"granted_by"`
+	CreatedAt time.Time `                                                     json:"created_at"`
+}
+
+// ServerRule — сохранённое значение правила сервера (A2S_RULES), обновляется поллером
+type ServerRule struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"                          json:"-"`
+	ServerID  uint      `gorm:"uniqueIndex:idx_rule_srv_key,priority:1;not null"  json:"-"`
+	Key       string    `gorm:"type:varchar(128);uniqueIndex:idx_rule_srv_key,priority:2;not null" json:"key"`
+	Value     string    `gorm:"type:varchar(512)"                                 json:"value"`
+	UpdatedAt time.Time `                                                         json:"updated_at"`
+}
+
 // DiscordConfig — настройки Discord-виджета (webhook + persistent message)
 type DiscordConfig struct {
 	ID             uint   `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -312,7 +501,6 @@ type DiscordConfig struct {
 type AlertsConfig struct {
 	ID             uint   `gorm:"primaryKey;autoIncrement" json:"id"`
 	ServerID       uint   `gorm:"uniqueIndex;not null"     json:"server_id"`
-	ThresholdCPU   int    `gorm:"default:90"               json:"threshold_cpu"`
 	OfflineTimeout int    `gorm:"default:5"                json:"offline_timeout"` // минуты
 	TgChatID       string `gorm:"type:varchar(50)"         json:"tg_chat_id"`
 	Enabled        bool   `gorm:"default:true"             json:"enabled"`
@@ -320,6 +508,37 @@ type AlertsConfig struct {
 	EmailTo        string `gorm:"type:varchar(200)"        json:"email_to"`
 }
 
+// Условия правил оповещения (AlertRule.Condition)
+const (
+	AlertRulePlayersAbove   = "players_above"   // игроков больше Threshold
+	AlertRulePlayersBelow   = "players_below"   // игроков меньше Threshold (сервер онлайн)
+	AlertRulePingAbove      = "ping_above"      // пинг выше Threshold мс
+	AlertRuleServerFull     = "server_full"     // заняты все слоты
+	AlertRuleMapChanged     = "map_changed"     // сменилась карта
+	AlertRuleNameChanged    = "name_changed"    // сменилось имя сервера
+	AlertRuleVersionChanged = "version_changed" // сменилась версия сервера
+)
+
+// AlertRule — правило оповещения по данным опроса. Пороговые условия срабатывают, когда
+// держатся DurationMin минут подряд, изменения — сразу; повторно не чаще раза в CooldownMin.
+// Пустые получатели берутся из AlertsConfig сервера.
+type AlertRule struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement"    json:"id"`
+	ServerID    uint       `gorm:"index;not null"              json:"server_id"`
+	Name        string     `gorm:"type:varchar(100)"           json:"name"`
+	Condition   string     `gorm:"type:varchar(32);not null"   json:"condition"`
+	Threshold   int        `gorm:"default:0"                   json:"threshold"`
+	DurationMin int        `gorm:"default:0"                   json:"duration_min"`
+	CooldownMin int        `gorm:"default:30"                  json:"cooldown_min"`
+	Enabled     bool       `gorm:"default:true"                json:"enabled"`
+	TgChatID    string     `gorm:"type:varchar(50)"            json:"tg_chat_id"`
+	EmailTo     string     `gorm:"type:varchar(200)"           json:"email_to"`
+	WebhookURL  string     `gorm:"type:varchar(500)"           json:"webhook_url"` // Discord webhook
+	LastFiredAt *time.Time `                                   json:"last_fired_at"`
+	CreatedAt   time.Time  `                                   json:"created_at"`
+	UpdatedAt   time.Time  `                                   json:"updated_at"`
+}
+
 // UserSession — активная сессия пользователя (токен)
 type UserSession struct {
 	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math"
 	"net"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,10 +20,42 @@ const (
 	a2sChallenge = "\xFF\xFF\xFF\xFF\x54Source Engine Query\x00"
 )
 
+// a2sProtocol — Source Engine Query (A2S_INFO / A2S_PLAYER / A2S_RULES)
+type a2sProtocol struct{}
+
+func (a2sProtocol) Name() string               { return "a2s" }
+func (a2sProtocol) DefaultPort() uint16        { return 27015 }
+func (a2sProtocol) Capabilities() Capabilities { return Capabilities{Players: true, Rules: true} }
+
+func (a2sProtocol) Info(ip string, port uint16) (*models.ServerStatus, error) {
+	return QuerySource(ip, port)
+}
+
+func (a2sProtocol) Players(ip string, port uint16) ([]models.ServerPlayer, error) {
+	return QuerySourcePlayers(ip, port)
+}
+
+func (a2sProtocol) Rules(ip string, port uint16) (map[string]string, error) {
+	return QuerySourceRules(ip, port)
+}
+
+func init() {
+	RegisterProtocol(a2sProtocol{})
+	RegisterGame("source", "Source", "a2s", 0)
+	RegisterGame("gmod", "Garry's Mod", "a2s", 0)
+	RegisterGame("rust", "Rust", "a2s", 28015)
+	RegisterGame("valheim", "Valheim", "a2s", 2457)
+	RegisterGame("dayz", "DayZ", "a2s", 27016)
+	RegisterGame("squad", "Squad", "a2s", 27165)
+	RegisterGame("arma3", "Arma 3", "a2s", 2303)
+	RegisterGame("vrising", "V Rising", "a2s", 27016)
+	RegisterGame("icarus", "Icarus", "a2s", 27015)
+	RegisterGame("terraria", "Terraria", "a2s", 7777)
+}
+
 // QuerySource выполняет A2S_INFO запрос к Source-совместимому серверу (CS2, TF2, Rust и т.д.)
 func QuerySource(ip string, port uint16) (*models.ServerStatus, error) {
-	addr := fmt.Sprintf("%s:%d", ip, port)
-	conn, err := net.DialTimeout("udp", addr, udpTimeout)
+	conn, err := net.DialTimeout("udp", hostPort(ip, port), udpTimeout)
 	if err != nil {
 		return nil, fmt.Errorf("dial failed: %w", err)
 	}
@@ -31,34 +65,13 @@ func QuerySource(ip string, port uint16) (*models.ServerStatus, error) {
 		return nil, err
 	}
 
-	start := time.Now()
-	if _, err = conn.Write([]byte(a2sChallenge)); err != nil {
-		return nil, fmt.Errorf("write failed: %w", err)
-	}
-
-	buf := make([]byte, 1400)
-	n, err := conn.Read(buf)
+	data, rtt, err := a2sRequest(conn, func(challenge []byte) []byte {
+		return append([]byte(a2sChallenge), challenge...)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("read failed: %w", err)
-	}
-	pingMS := int(time.Since(start).Milliseconds())
-
-	data := buf[:n]
-
-	// Некоторые серверы требуют challenge ответа
-	if len(data) >= 9 && data[4] == 0x41 {
-		// Получили challenge number, повторяем с ним
-		challenge := data[5:9]
-		request := append([]byte("\xFF\xFF\xFF\xFF\x54Source Engine Query\x00"), challenge...)
-		if _, err = conn.Write(request); err != nil {
-			return nil, err
-		}
-		n, err = conn.Read(buf)
-		if err != nil {
-			return nil, err
-		}
-		data = buf[:n]
+		return nil, err
 	}
+	pingMS := int(rtt.Milliseconds())
 
 	return parseA2SInfo(data, pingMS)
 }
@@ -104,50 +117,123 @@ func parseA2SInfo(data []byte, pingMS int) (*models.ServerStatus, error) {
 	binary.Read(r, binary.LittleEndian, &playersMax) //nolint:errcheck
 	binary.Read(r, binary.LittleEndian, &bots)       //nolint:errcheck
 
-	return &models.ServerStatus{
+	// Server type ('d' dedicated, 'l' listen, 'p' SourceTV), environment ('l', 'w', 'm'/'o')
+	var serverType, environment, visibility, vac byte
+	binary.Read(r, binary.LittleEndian, &serverType)  //nolint:errcheck
+	binary.Read(r, binary.LittleEndian, &environment) //nolint:errcheck
+	binary.Read(r, binary.LittleEndian, &visibility)  //nolint:errcheck
+	binary.Read(r, binary.LittleEndian, &vac)         //nolint:errcheck
+
+	// The Ship (AppID 2400): mode, witnesses, duration
+	if appID == 2400 {
+		r.Seek(3, io.SeekCurrent) //nolint:errcheck
+	}
+
+	version := truncate(readNullString(r), 64)
+
+	// Боты входят в общее число игроков — считаем только людей
+	humans := int(playersNow) - int(bots)
+	if humans < 0 {
+		humans = 0
+	}
+
+	status := &models.ServerStatus{
 		OnlineStatus: true,
-		PlayersNow:   int(playersNow),
+		PlayersNow:   humans,
 		PlayersMax:   int(playersMax),
+		Bots:         int(bots),
 		CurrentMap:   mapName,
 		ServerName:   serverName,
 		PingMS:       pingMS,
-	}, nil
+		ServerType:   a2sPrintable(serverType),
+		Environment:  a2sPrintable(environment),
+		Password:     visibility == 1,
+		VAC:          vac == 1,
+		Version:      version,
+		AppID:        uint64(appID),
+	}
+	parseA2SInfoEDF(r, status)
+	return status, nil
 }
 
-// QuerySourcePlayers выполняет A2S_PLAYER запрос и возвращает список игроков
-func QuerySourcePlayers(ip string, port uint16) ([]models.ServerPlayer, error) {
-	addr := fmt.Sprintf("%s:%d", ip, port)
-	conn, err := net.DialTimeout("udp", addr, udpTimeout)
+// Флаги Extra Data Flag (EDF) в конце ответа A2S_INFO
+const (
+	edfGameID   = 0x01
+	edfSteamID  = 0x10
+	edfKeywords = 0x20
+	edfSourceTV = 0x40
+	edfGamePort = 0x80
+)
+
+// parseA2SInfoEDF читает необязательный блок EDF (порт, SteamID, keywords, 64-битный GameID)
+func parseA2SInfoEDF(r *bytes.Reader, status *models.ServerStatus) {
+	edf, err := r.ReadByte()
 	if err != nil {
-		return nil, fmt.Errorf("dial failed: %w", err)
+		return
 	}
-	defer conn.Close()
-	conn.SetDeadline(time.Now().Add(udpTimeout)) //nolint:errcheck
+	if edf&edfGamePort != 0 {
+		var port uint16
+		binary.Read(r, binary.LittleEndian, &port) //nolint:errcheck
+		status.GamePort = port
+	}
+	if edf&edfSteamID != 0 {
+		var steamID uint64
+		binary.Read(r, binary.LittleEndian, &steamID) //nolint:errcheck
+		if steamID != 0 {
+			status.SteamID = strconv.FormatUint(steamID, 10)
+		}
+	}
+	if edf&edfSourceTV != 0 {
+		var tvPort uint16
+		binary.Read(r, binary.LittleEndian, &tvPort) //nolint:errcheck
+		_ = readNullString(r)                        // SourceTV name
+	}
+	if edf&edfKeywords != 0 {
+		status.Keywords = truncate(readNullString(r), 512)
+	}
+	if edf&edfGameID != 0 {
+		var gameID uint64
+		if err := binary.Read(r, binary.LittleEndian, &gameID); err == nil {
+			// Младшие 24 бита GameID — полный AppID (в 16-битном поле он может быть обрезан)
+			status.AppID = gameID & 0xFFFFFF
+		}
+	}
+}
+
+// truncate обрезает строку до n байт, чтобы она поместилась в колонку БД
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
 
-	// Шаг 1: отправляем challenge запрос
-	challengeReq := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x55, 0xFF, 0xFF, 0xFF, 0xFF}
-	if _, err := conn.Write(challengeReq); err != nil {
-		return nil, fmt.Errorf("write challenge failed: %w", err)
+// a2sPrintable превращает однобайтовый код ('d', 'l', 'w') в строку
+func a2sPrintable(b byte) string {
+	if b < 0x20 || b > 0x7E {
+		return ""
 	}
+	return string(rune(b))
+}
 
-	buf := make([]byte, 4096)
-	n, err := conn.Read(buf)
+// QuerySourcePlayers выполняет A2S_PLAYER запрос и возвращает список игроков
+func QuerySourcePlayers(ip string, port uint16) ([]models.ServerPlayer, error) {
+	conn, err := net.DialTimeout("udp", hostPort(ip, port), udpTimeout)
 	if err != nil {
-		return nil, fmt.Errorf("read challenge response failed: %w", err)
+		return nil, fmt.Errorf("dial failed: %w", err)
 	}
-	data := buf[:n]
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(udpTimeout)) //nolint:errcheck
 
-	// Если получили challenge (0x41), повторяем с challenge bytes
-	if len(data) >= 9 && data[4] == 0x41 {
-		req := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x55, data[5], data[6], data[7], data[8]}
-		if _, err := conn.Write(req); err != nil {
-			return nil, fmt.Errorf("write player request failed: %w", err)
-		}
-		n, err = conn.Read(buf)
-		if err != nil {
-			return nil, fmt.Errorf("read player response failed: %w", err)
+	// Без challenge отправляем -1, сервер ответит 0x41 с настоящим значением
+	data, _, err := a2sRequest(conn, func(challenge []byte) []byte {
+		if challenge == nil {
+			challenge = []byte{0xFF, 0xFF, 0xFF, 0xFF}
 		}
-		data = buf[:n]
+		return append([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x55}, challenge...)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Парсим ответ A2S_PLAYER (0x44)
@@ -170,22 +256,67 @@ func QuerySourcePlayers(ip string, port uint16) ([]models.ServerPlayer, error) {
 		}
 		// name (null-terminated string)
 		name := readNullString(r)
-		// score (int32 LE) — пропускаем
+		// score (int32 LE)
 		var score int32
 		binary.Read(r, binary.LittleEndian, &score) //nolint:errcheck
-		// duration (float32 LE) — пропускаем
+		// duration (float32 LE) — секунды с момента подключения
 		var durationBits uint32
 		binary.Read(r, binary.LittleEndian, &durationBits) //nolint:errcheck
-		_ = math.Float32frombits(durationBits)
+		duration := float64(math.Float32frombits(durationBits))
+		if math.IsNaN(duration) || duration < 0 || duration > math.MaxInt32 {
+			duration = 0
+		}
 
 		name = strings.TrimSpace(name)
 		if name != "" {
-			players = append(players, models.ServerPlayer{Name: name})
+			players = append(players, models.ServerPlayer{Name: name, Score: int(score), Duration: int(duration)})
 		}
 	}
 	return players, nil
 }
 
+// QuerySourceRules выполняет A2S_RULES запрос и возвращает cvars сервера.
+// Ответ у модовых серверов (Rust, DayZ) обычно разбит на несколько пакетов.
+func QuerySourceRules(ip string, port uint16) (map[string]string, error) {
+	conn, err := net.DialTimeout("udp", hostPort(ip, port), udpTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("dial failed: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(udpTimeout)) //nolint:errcheck
+
+	data, _, err := a2sRequest(conn, func(challenge []byte) []byte {
+		if challenge == nil {
+			challenge = []byte{0xFF, 0xFF, 0xFF, 0xFF}
+		}
+		return append([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x56}, challenge...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Парсим ответ A2S_RULES (0x45)
+	if len(data) < 7 || data[4] != 0x45 {
+		return nil, fmt.Errorf("unexpected rules response type")
+	}
+
+	r := bytes.NewReader(data[5:])
+	var ruleCount uint16
+	if err := binary.Read(r, binary.LittleEndian, &ruleCount); err != nil {
+		return nil, err
+	}
+
+	rules := make(map[string]string, ruleCount)
+	for i := uint16(0); i < ruleCount && r.Len() > 0; i++ {
+		name := readNullString(r)
+		value := readNullString(r)
+		if name != "" {
+			rules[name] = value
+		}
+	}
+	return rules, nil
+}
+
 func readNullString(r *bytes.Reader) string {
 	var out []byte
 	for {
@@ -0,0 +1,197 @@
+package poller
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"time"
+)
+
+// Заголовки A2S-пакетов: -1 — ответ в одном датаграмме, -2 — часть разбитого ответа
+// https://developer.valvesoftware.com/wiki/Server_queries#Multi-packet_Response_Format
+const (
+	a2sSinglePacket = -1
+	a2sSplitPacket  = -2
+
+	a2sMaxDatagram = 4096
+	// Предохранители от мусорных заголовков
+	a2sMaxSplitParts   = 64
+	a2sMaxDecompressed = 1 << 20
+)
+
+// a2sSplitPart — одна часть разбитого ответа
+type a2sSplitPart struct {
+	id      uint32
+	total   int
+	number  int
+	payload []byte
+
+	// Только для первой части сжатого ответа Source
+	decompressedSize uint32
+	checksum         uint32
+}
+
+// a2sRead читает ответ сервера, при необходимости собирая его из нескольких датаграмм.
+// Возвращает данные в формате одиночного пакета (с заголовком FF FF FF FF).
+func a2sRead(conn net.Conn) ([]byte, error) {
+	buf := make([]byte, a2sMaxDatagram)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	if n < 4 {
+		return nil, fmt.Errorf("response too short: %d bytes", n)
+	}
+
+	switch int32(binary.LittleEndian.Uint32(buf[:4])) {
+	case a2sSinglePacket:
+		return append([]byte(nil), buf[:n]...), nil
+	case a2sSplitPacket:
+	default:
+		return nil, fmt.Errorf("unknown packet header 0x%08X", binary.LittleEndian.Uint32(buf[:4]))
+	}
+
+	// Формат (Source или GoldSrc) определяется по первой полученной части и дальше не меняется
+	goldSrc := a2sLooksGoldSrc(buf[:n])
+	first, err := parseA2SSplitPart(buf[:n], goldSrc)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]*a2sSplitPart, first.total)
+	parts[first.number] = first
+	received := 1
+	for received < first.total {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, fmt.Errorf("read split packet %d/%d: %w", received+1, first.total, err)
+		}
+		if n < 4 || int32(binary.LittleEndian.Uint32(buf[:4])) != a2sSplitPacket {
+			continue // одиночный пакет от предыдущего запроса
+		}
+		part, err := parseA2SSplitPart(buf[:n], goldSrc)
+		if err != nil {
+			return nil, err
+		}
+		if part.id != first.id || part.total != first.total {
+			continue // часть чужого ответа
+		}
+		if parts[part.number] == nil {
+			received++
+		}
+		parts[part.number] = part
+	}
+
+	var payload bytes.Buffer
+	for _, p := range parts {
+		payload.Write(p.payload)
+	}
+
+	// Старший бит ID — ответ сжат bzip2 (только Source)
+	if !goldSrc && first.id&0x80000000 != 0 {
+		return a2sDecompress(payload.Bytes(), parts[0].decompressedSize, parts[0].checksum)
+	}
+	return payload.Bytes(), nil
+}
+
+// a2sLooksGoldSrc различает форматы заголовка части по байту 8.
+// Source:  ID(4) Total(1) Number(1) Size(2) …
+// GoldSrc: ID(4) Number<<4|Total(1) …
+// Если байт 8 не складывается в номер и число частей GoldSrc — это Source. Ненулевой
+// номер GoldSrc в трактовке Source означает ответ из 16+ частей, которых A2S не шлёт.
+// Остаётся номер 0: первая часть GoldSrc начинается с заголовка FF FF FF FF, а в Source
+// на этом месте стоит номер части, меньший Total.
+func a2sLooksGoldSrc(data []byte) bool {
+	if len(data) < 12 {
+		return true
+	}
+	total, number := data[8]&0x0F, data[8]>>4
+	if total == 0 || number >= total {
+		return false
+	}
+	if number > 0 {
+		return true
+	}
+	return data[9] >= data[8]
+}
+
+func parseA2SSplitPart(data []byte, goldSrc bool) (*a2sSplitPart, error) {
+	if len(data) < 9 {
+		return nil, fmt.Errorf("split packet too short: %d bytes", len(data))
+	}
+	p := &a2sSplitPart{id: binary.LittleEndian.Uint32(data[4:8])}
+
+	if goldSrc {
+		p.total = int(data[8] & 0x0F)
+		p.number = int(data[8] >> 4)
+		p.payload = data[9:]
+	} else {
+		if len(data) < 12 {
+			return nil, fmt.Errorf("split packet too short: %d bytes", len(data))
+		}
+		p.total = int(data[8])
+		p.number = int(data[9])
+		// data[10:12] — максимальный размер части, для сборки не нужен
+		rest := data[12:]
+		if p.id&0x80000000 != 0 && p.number == 0 {
+			if len(rest) < 8 {
+				return nil, fmt.Errorf("compressed split header too short")
+			}
+			p.decompressedSize = binary.LittleEndian.Uint32(rest[0:4])
+			p.checksum = binary.LittleEndian.Uint32(rest[4:8])
+			rest = rest[8:]
+		}
+		p.payload = rest
+	}
+
+	if p.total == 0 || p.total > a2sMaxSplitParts || p.number >= p.total {
+		return nil, fmt.Errorf("invalid split packet %d/%d", p.number, p.total)
+	}
+	p.payload = append([]byte(nil), p.payload...)
+	return p, nil
+}
+
+func a2sDecompress(data []byte, size, checksum uint32) ([]byte, error) {
+	if size == 0 || size > a2sMaxDecompressed {
+		return nil, fmt.Errorf("invalid decompressed size %d", size)
+	}
+	out := make([]byte, size)
+	if _, err := io.ReadFull(bzip2.NewReader(bytes.NewReader(data)), out); err != nil {
+		return nil, fmt.Errorf("bzip2: %w", err)
+	}
+	if crc32.ChecksumIEEE(out) != checksum {
+		return nil, fmt.Errorf("bzip2: checksum mismatch")
+	}
+	return out, nil
+}
+
+// a2sRequest отправляет запрос и проходит challenge (0x41), если сервер его требует.
+// build получает challenge (nil для первого запроса) и возвращает пакет запроса.
+// rtt — время первого обмена, оно же пинг сервера.
+func a2sRequest(conn net.Conn, build func(challenge []byte) []byte) (data []byte, rtt time.Duration, err error) {
+	start := time.Now()
+	if _, err = conn.Write(build(nil)); err != nil {
+		return nil, 0, fmt.Errorf("write failed: %w", err)
+	}
+	data, err = a2sRead(conn)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read failed: %w", err)
+	}
+	rtt = time.Since(start)
+
+	// Некоторые серверы отвечают новым challenge повторно — ограничиваем число попыток
+	for attempt := 0; attempt < 3 && len(data) >= 9 && data[4] == 0x41; attempt++ {
+		if _, err = conn.Write(build(data[5:9])); err != nil {
+			return nil, 0, fmt.Errorf("write failed: %w", err)
+		}
+		data, err = a2sRead(conn)
+		if err != nil {
+			return nil, 0, fmt.Errorf("read failed: %w", err)
+		}
+	}
+	return data, rtt, nil
+}
@@ -0,0 +1,175 @@
+package poller
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// a2sPayload — ответ A2S_PLAYER в формате одиночного пакета, который собирается из частей
+var a2sPayload = bytes.Repeat([]byte("\xff\xff\xff\xffDplayers-compressed-"), 4)
+
+// a2sPayloadBzip2 — a2sPayload, сжатый bzip2 (в стандартной библиотеке нет компрессора)
+const a2sPayloadBzip2 = "\x42\x5a\x68\x39\x31\x41\x59\x26\x53\x59\x25\x3b\x36\xaf\x00\x00" +
+	"\x31\xd5\x80\xc0\x00\x00\x02\x04\x00\x2e\x06\xd8\x20\x00\x00\xa0\x00\x50\xa6\x00\x00\x2a" +
+	"\xa8\x69\x82\x33\xd4\x86\xcb\x94\x32\x54\xc9\x52\x10\x84\x36\x68\x87\xa6\x0b\x18\x3c\x21" +
+	"\x0e\xbf\x38\x2e\xe4\x8a\x70\xa1\x20\x4a\x76\x6d\x5e"
+
+func sourcePart(id uint32, total, number byte, payload []byte) []byte {
+	b := []byte{0xFE, 0xFF, 0xFF, 0xFF}
+	b = binary.LittleEndian.AppendUint32(b, id)
+	b = append(b, total, number)
+	b = binary.LittleEndian.AppendUint16(b, 1248)
+	return append(b, payload...)
+}
+
+// compressedFirstPart — первая часть сжатого ответа Source с размером и CRC32 распакованных данных
+func compressedFirstPart(id uint32, total byte, size, crc uint32, payload []byte) []byte {
+	head := binary.LittleEndian.AppendUint32(nil, size)
+	head = binary.LittleEndian.AppendUint32(head, crc)
+	return sourcePart(id|0x80000000, total, 0, append(head, payload...))
+}
+
+func goldSrcPart(id uint32, total, number byte, payload []byte) []byte {
+	b := []byte{0xFE, 0xFF, 0xFF, 0xFF}
+	b = binary.LittleEndian.AppendUint32(b, id)
+	b = append(b, number<<4|total)
+	return append(b, payload...)
+}
+
+// readPackets отдаёт датаграммы a2sRead по одной через net.Pipe
+func readPackets(t *testing.T, packets [][]byte) ([]byte, error) {
+	t.Helper()
+	server, client := net.Pipe()
+	defer client.Close()
+	go func() {
+		defer server.Close()
+		for _, pkt := range packets {
+			if _, err := server.Write(pkt); err != nil {
+				return
+			}
+		}
+	}()
+	_ = client.SetDeadline(time.Now().Add(2 * time.Second))
+	return a2sRead(client)
+}
+
+func TestA2SRead(t *testing.T) {
+	head, tail := a2sPayload[:30], a2sPayload[30:]
+	bz := []byte(a2sPayloadBzip2)
+	bzHead, bzTail := bz[:40], bz[40:]
+	size := uint32(len(a2sPayload))
+	crc := crc32.ChecksumIEEE(a2sPayload)
+
+	tests := []struct {
+		name    string
+		packets [][]byte
+		wantErr string
+	}{
+		{
+			name:    "single packet",
+			packets: [][]byte{a2sPayload},
+		},
+		{
+			name:    "source in order",
+			packets: [][]byte{sourcePart(7, 2, 0, head), sourcePart(7, 2, 1, tail)},
+		},
+		{
+			name:    "source out of order",
+			packets: [][]byte{sourcePart(7, 2, 1, tail), sourcePart(7, 2, 0, head)},
+		},
+		{
+			name: "source skips foreign parts and stray single packets",
+			packets: [][]byte{
+				sourcePart(7, 2, 0, head),
+				{0xFF, 0xFF, 0xFF, 0xFF, 'A', 1, 2, 3, 4},
+				sourcePart(8, 2, 1, []byte("other response")),
+				sourcePart(7, 2, 1, tail),
+			},
+		},
+		{
+			name:    "source duplicate part",
+			packets: [][]byte{sourcePart(7, 3, 0, head[:10]), sourcePart(7, 3, 0, head[:10]), sourcePart(7, 3, 2, tail), sourcePart(7, 3, 1, head[10:])},
+		},
+		{
+			name:    "goldsrc in order",
+			packets: [][]byte{goldSrcPart(7, 2, 0, head), goldSrcPart(7, 2, 1, tail)},
+		},
+		{
+			name:    "goldsrc out of order",
+			packets: [][]byte{goldSrcPart(7, 2, 1, tail), goldSrcPart(7, 2, 0, head)},
+		},
+		{
+			name:    "source bzip2",
+			packets: [][]byte{compressedFirstPart(7, 2, size, crc, bzHead), sourcePart(7|0x80000000, 2, 1, bzTail)},
+		},
+		{
+			name:    "source bzip2 out of order",
+			packets: [][]byte{sourcePart(7|0x80000000, 2, 1, bzTail), compressedFirstPart(7, 2, size, crc, bzHead)},
+		},
+		{
+			name:    "source bzip2 checksum mismatch",
+			packets: [][]byte{compressedFirstPart(7, 2, size, crc^1, bzHead), sourcePart(7|0x80000000, 2, 1, bzTail)},
+			wantErr: "checksum mismatch",
+		},
+		{
+			name:    "source bzip2 invalid size",
+			packets: [][]byte{compressedFirstPart(7, 1, 0, crc, bz)},
+			wantErr: "invalid decompressed size",
+		},
+		{
+			name:    "part number out of range",
+			packets: [][]byte{sourcePart(7, 2, 0, head), sourcePart(7, 2, 5, tail)},
+			wantErr: "invalid split packet",
+		},
+		{
+			name:    "unknown header",
+			packets: [][]byte{{0x01, 0x02, 0x03, 0x04, 0x05}},
+			wantErr: "unknown packet header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readPackets(t, tt.packets)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("a2sRead() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("a2sRead() error = %v", err)
+			}
+			if !bytes.Equal(got, a2sPayload) {
+				t.Fatalf("a2sRead() = %q, want %q", got, a2sPayload)
+			}
+		})
+	}
+}
+
+func TestA2SLooksGoldSrc(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"source first part", sourcePart(1, 3, 0, []byte("\xff\xff\xff\xffD")), false},
+		{"source last part", sourcePart(1, 3, 2, []byte("tail")), false},
+		{"goldsrc first part", goldSrcPart(1, 3, 0, []byte("\xff\xff\xff\xffD")), true},
+		{"goldsrc later part", goldSrcPart(1, 3, 2, []byte("player name")), true},
+		// Данные, которые в трактовке Source дают правдоподобные Number=1 и Size=1248
+		{"goldsrc later part with low first byte", goldSrcPart(1, 3, 1, []byte{0x01, 0xE0, 0x04, 0x00}), true},
+		{"source sixteen parts", sourcePart(1, 16, 3, []byte("tail")), false},
+		{"too short", []byte{0xFE, 0xFF, 0xFF, 0xFF, 1, 0, 0, 0, 0x02}, true},
+	}
+	for _, tt := range tests {
+		if got := a2sLooksGoldSrc(tt.data); got != tt.want {
+			t.Errorf("%s: a2sLooksGoldSrc() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package poller
+
+import (
+	"log"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// AgentReportMaxAge — отчёты агентов старше этого не участвуют в голосовании
+const AgentReportMaxAge = 3 * emptyPollInterval
+
+// agentQuorumOffline решает, считать ли сервер офлайн, когда его не видит центральный поллер.
+// Голосуют центральный узел (всегда «офлайн») и агенты со свежими отчётами; сервер офлайн,
+// если так считает строгое большинство. Без агентов решает центральный узел.
+func agentQuorumOffline(serverID uint, logDisagreement bool) bool {
+	var votes []models.RegionStatus
+	database.DB.
+		Joins("JOIN poll_agents ON poll_agents.id = region_statuses.agent_id AND poll_agents.enabled = ?", true).
+		Where("region_statuses.server_id = ? AND region_statuses.checked_at > ?", serverID, time.Now().Add(-AgentReportMaxAge)).
+		Find(&votes)
+
+	offline, total := 1, 1+len(votes)
+	for _, v := range votes {
+		if !v.OnlineStatus {
+			offline++
+		}
+	}
+	if offline*2 > total {
+		return true
+	}
+	if logDisagreement {
+		log.Printf("[Poller] server %d unreachable from here but online for %d of %d agents — not marking offline",
+			serverID, total-offline, len(votes))
+	}
+	return false
+}
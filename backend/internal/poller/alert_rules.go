@@ -0,0 +1,227 @@
+package poller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/maintenance"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// alertRulesReload — как часто перечитываются правила оповещения из БД
+const alertRulesReload = 30 * time.Second
+
+// ruleStreak — серия опросов, в которых пороговое условие правила выполняется
+type ruleStreak struct {
+	since time.Time // первый опрос серии
+	fired bool      // правило уже сработало в этой серии
+}
+
+// seenStatus — последние известные карта и имя сервера для правил на изменение
+type seenStatus struct {
+	mapName string
+	name    string
+}
+
+// alertRules — состояние проверки правил оповещения.
+// Доступ только из processResults — мьютекс не нужен.
+type alertRules struct {
+	byServer map[uint][]models.AlertRule
+	loadedAt time.Time
+	streaks  map[uint]*ruleStreak // по ID правила
+	seen     map[uint]*seenStatus // по ID сервера
+}
+
+func newAlertRules() *alertRules {
+	return &alertRules{
+		streaks: make(map[uint]*ruleStreak),
+		seen:    make(map[uint]*seenStatus),
+	}
+}
+
+// reload перечитывает включённые правила не чаще alertRulesReload и забывает серии удалённых
+func (a *alertRules) reload() {
+	if time.Since(a.loadedAt) < alertRulesReload {
+		return
+	}
+	a.loadedAt = time.Now()
+	var rules []models.AlertRule
+	if err := database.DB.Where("enabled = ?", true).Find(&rules).Error; err != nil {
+		log.Printf("[Poller] загрузка правил оповещения: %v", err)
+		return
+	}
+	a.byServer = make(map[uint][]models.AlertRule)
+	alive := make(map[uint]bool, len(rules))
+	for _, r := range rules {
+		a.byServer[r.ServerID] = append(a.byServer[r.ServerID], r)
+		alive[r.ID] = true
+	}
+	for id := range a.streaks {
+		if !alive[id] {
+			delete(a.streaks, id)
+		}
+	}
+}
+
+// previous возвращает прошлые карту и имя сервера. При первом опросе после запуска
+// берёт их из сохранённого статуса, чтобы смена во время перезапуска не потерялась.
+func (a *alertRules) previous(serverID uint) *seenStatus {
+	s, ok := a.seen[serverID]
+	if !ok {
+		var stored models.ServerStatus
+		database.DB.Select("current_map", "server_name").Where("server_id = ?", serverID).First(&stored)
+		s = &seenStatus{mapName: stored.CurrentMap, name: stored.ServerName}
+		a.seen[serverID] = s
+	}
+	return s
+}
+
+// thresholdDetail проверяет пороговое условие правила; detail — текст оповещения без имени сервера
+func thresholdDetail(r *models.AlertRule, st *models.ServerStatus) (holds bool, detail string) {
+	if !st.OnlineStatus {
+		return false, ""
+	}
+	switch r.Condition {
+	case models.AlertRulePlayersAbove:
+		return st.PlayersNow > r.Threshold, fmt.Sprintf("👥 игроков больше %d (сейчас %d)", r.Threshold, st.PlayersNow)
+	case models.AlertRulePlayersBelow:
+		return st.PlayersNow < r.Threshold, fmt.Sprintf("👥 игроков меньше %d (сейчас %d)", r.Threshold, st.PlayersNow)
+	case models.AlertRulePingAbove:
+		return st.PingMS > r.Threshold, fmt.Sprintf("📶 пинг %d мс (порог %d мс)", st.PingMS, r.Threshold)
+	case models.AlertRuleServerFull:
+		return st.PlayersMax > 0 && st.PlayersNow >= st.PlayersMax, fmt.Sprintf("🈵 сервер заполнен (%d/%d)", st.PlayersNow, st.PlayersMax)
+	}
+	return false, ""
+}
+
+// evaluateAlertRules проверяет правила оповещения сервера по результату опроса.
+// Вызывается из processResults до записи статуса; versionChanged — результат detectVersionChange.
+func (p *Poller) evaluateAlertRules(serverID uint, st *models.ServerStatus, versionChanged bool) {
+	a := p.alerts
+	a.reload()
+	prev := a.previous(serverID)
+	prevMap, prevName := prev.mapName, prev.name
+	if st.OnlineStatus {
+		if st.CurrentMap != "" {
+			prev.mapName = st.CurrentMap
+		}
+		if st.ServerName != "" {
+			prev.name = st.ServerName
+		}
+	}
+
+	now := time.Now()
+	rules := a.byServer[serverID]
+	for i := range rules {
+		r := &rules[i]
+		var detail string
+		switch r.Condition {
+		case models.AlertRuleMapChanged:
+			if !st.OnlineStatus || prevMap == "" || st.CurrentMap == "" || st.CurrentMap == prevMap {
+				continue
+			}
+			detail = fmt.Sprintf("🗺️ карта сменилась: %s → %s", html.EscapeString(prevMap), html.EscapeString(st.CurrentMap))
+		case models.AlertRuleNameChanged:
+			if !st.OnlineStatus || prevName == "" || st.ServerName == "" || st.ServerName == prevName {
+				continue
+			}
+			detail = fmt.Sprintf("🏷️ имя сервера сменилось: %s → %s", html.EscapeString(prevName), html.EscapeString(st.ServerName))
+		case models.AlertRuleVersionChanged:
+			if !versionChanged {
+				continue
+			}
+			detail = fmt.Sprintf("🆕 версия сервера: %s", html.EscapeString(st.Version))
+		default:
+			holds, d := thresholdDetail(r, st)
+			s := a.streaks[r.ID]
+			if s == nil {
+				s = &ruleStreak{}
+				a.streaks[r.ID] = s
+			}
+			if !holds {
+				*s = ruleStreak{}
+				continue
+			}
+			if s.since.IsZero() {
+				s.since = now
+			}
+			if s.fired || now.Sub(s.since) < time.Duration(r.DurationMin)*time.Minute {
+				continue
+			}
+			if r.DurationMin > 0 {
+				d += fmt.Sprintf(", уже %d мин.", int(now.Sub(s.since).Minutes()))
+			}
+			detail = d
+		}
+
+		// Повторно правило срабатывает не раньше, чем через CooldownMin после прошлого раза;
+		// пороговое условие, которое всё ещё держится, сработает по окончании паузы
+		if r.LastFiredAt != nil && now.Sub(*r.LastFiredAt) < time.Duration(r.CooldownMin)*time.Minute {
+			continue
+		}
+		if s := a.streaks[r.ID]; s != nil {
+			s.fired = true
+		}
+		r.LastFiredAt = &now
+		database.DB.Model(&models.AlertRule{}).Where("id = ?", r.ID).Update("last_fired_at", now)
+
+		// Во время плановых работ правило считается сработавшим, но оповещение не отправляется
+		if maintenance.Active(serverID, now) {
+			continue
+		}
+		rule := *r
+		p.notify(func() { p.sendRuleAlert(&rule, detail) })
+	}
+}
+
+// sendRuleAlert отправляет оповещение правила его получателям, а если они не заданы — получателям AlertsConfig.
+// detail уже экранирован для HTML-разметки Telegram.
+func (p *Poller) sendRuleAlert(r *models.AlertRule, detail string) {
+	var srv models.Server
+	if err := database.DB.First(&srv, r.ServerID).Error; err != nil {
+		return
+	}
+	text := fmt.Sprintf("🔔 <b>%s</b> — %s", html.EscapeString(srv.Title), detail)
+	subject := "🔔 " + srv.Title
+	if r.Name != "" {
+		text += fmt.Sprintf(" [%s]", html.EscapeString(r.Name))
+		subject += " — " + r.Name
+	}
+
+	cfg := models.AlertsConfig{TgChatID: r.TgChatID, EmailTo: r.EmailTo}
+	if r.TgChatID == "" && r.EmailTo == "" && r.WebhookURL == "" {
+		if err := database.DB.Where("server_id = ? AND enabled = ?", r.ServerID, true).First(&cfg).Error; err != nil {
+			return
+		}
+	}
+	p.sendAlert(&cfg, subject, text)
+	if r.WebhookURL != "" {
+		if err := sendWebhookText(r.WebhookURL, stripHTML(text)); err != nil {
+			log.Printf("[Poller] webhook alert error (rule %d): %v", r.ID, err)
+		}
+	}
+}
+
+// sendWebhookText отправляет простое текстовое сообщение в Discord webhook.
+// Упоминания отключены: в тексте имена и карты, которые задаёт игровой сервер (например, "@everyone").
+func sendWebhookText(webhookURL, content string) error {
+	body, _ := json.Marshal(map[string]interface{}{
+		"content":          content,
+		"allowed_mentions": map[string][]string{"parse": {}},
+	})
+	resp, err := pollerHTTPClient.Post(webhookURL, "application/json", bytes.NewReader(body)) //nolint:noctx
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
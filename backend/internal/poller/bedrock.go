@@ -0,0 +1,165 @@
+package poller
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// bedrockProtocol — Minecraft Bedrock Edition (RakNet Unconnected Ping)
+type bedrockProtocol struct{}
+
+func (bedrockProtocol) Name() string               { return "bedrock" }
+func (bedrockProtocol) DefaultPort() uint16        { return 19132 }
+func (bedrockProtocol) Capabilities() Capabilities { return Capabilities{} }
+
+func (bedrockProtocol) Info(ip string, port uint16) (*models.ServerStatus, error) {
+	return QueryBedrock(ip, port)
+}
+
+func (bedrockProtocol) Players(string, uint16) ([]models.ServerPlayer, error) {
+	return nil, ErrNotSupported
+}
+
+func (bedrockProtocol) Rules(string, uint16) (map[string]string, error) {
+	return nil, ErrNotSupported
+}
+
+func init() {
+	RegisterProtocol(bedrockProtocol{})
+	RegisterGame("minecraft_bedrock", "Minecraft Bedrock", "bedrock", 0)
+}
+
+// Идентификаторы пакетов RakNet
+// https://wiki.vg/Raknet_Protocol#Unconnected_Ping
+const (
+	raknetUnconnectedPing = 0x01
+	raknetUnconnectedPong = 0x1C
+)
+
+// raknetMagic — "offline message data ID", обязателен в каждом unconnected-пакете
+var raknetMagic = []byte{
+	0x00, 0xFF, 0xFF, 0x00, 0xFE, 0xFE, 0xFE, 0xFE,
+	0xFD, 0xFD, 0xFD, 0xFD, 0x12, 0x34, 0x56, 0x78,
+}
+
+// BedrockMOTD — разобранная строка статуса из Unconnected Pong
+type BedrockMOTD struct {
+	Edition    string // MCPE или MCEE (Education Edition)
+	MOTD       string
+	Protocol   int
+	Version    string
+	Players    int
+	MaxPlayers int
+	ServerGUID string
+	LevelName  string
+	GameMode   string
+	PortV4     uint16
+	PortV6     uint16
+}
+
+// QueryBedrock отправляет RakNet Unconnected Ping и разбирает MOTD из ответа
+func QueryBedrock(ip string, port uint16) (*models.ServerStatus, error) {
+	conn, err := net.DialTimeout("udp", hostPort(ip, port), udpTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("udp dial: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(udpTimeout)) //nolint:errcheck
+
+	var guid [8]byte
+	rand.Read(guid[:]) //nolint:errcheck
+
+	var req bytes.Buffer
+	req.WriteByte(raknetUnconnectedPing)
+	binary.Write(&req, binary.BigEndian, time.Now().UnixMilli()) //nolint:errcheck
+	req.Write(raknetMagic)
+	req.Write(guid[:])
+
+	start := time.Now()
+	if _, err := conn.Write(req.Bytes()); err != nil {
+		return nil, fmt.Errorf("write: %w", err)
+	}
+
+	resp := make([]byte, 2048)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+	pingMS := int(time.Since(start).Milliseconds())
+
+	// ID(1) + Time(8) + ServerGUID(8) + Magic(16) + StringLen(2)
+	const headerLen = 1 + 8 + 8 + 16 + 2
+	if n < headerLen || resp[0] != raknetUnconnectedPong {
+		return nil, fmt.Errorf("invalid RakNet pong (len=%d)", n)
+	}
+	if !bytes.Equal(resp[17:33], raknetMagic) {
+		return nil, fmt.Errorf("invalid RakNet magic")
+	}
+	strLen := int(binary.BigEndian.Uint16(resp[33:35]))
+	if headerLen+strLen > n {
+		return nil, fmt.Errorf("truncated RakNet pong")
+	}
+
+	motd, err := parseBedrockMOTD(string(resp[headerLen : headerLen+strLen]))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ServerStatus{
+		OnlineStatus: true,
+		PlayersNow:   motd.Players,
+		PlayersMax:   motd.MaxPlayers,
+		CurrentMap:   motd.LevelName,
+		ServerName:   strings.TrimSpace(mcFormatRegex.ReplaceAllString(motd.MOTD, "")),
+		PingMS:       pingMS,
+		Version:      truncate(motd.Version, 64),
+		GamePort:     motd.PortV4,
+		MOTDSpans:    mcMOTDSpansJSON(motd.MOTD),
+	}, nil
+}
+
+// parseBedrockMOTD разбирает строку вида
+// "MCPE;MOTD;Protocol;Version;Players;Max;ServerGUID;LevelName;GameMode;GameModeNum;PortV4;PortV6;"
+// Старые серверы присылают только первые 6 полей.
+func parseBedrockMOTD(s string) (*BedrockMOTD, error) {
+	f := strings.Split(s, ";")
+	if len(f) < 6 {
+		return nil, fmt.Errorf("invalid Bedrock MOTD: %q", s)
+	}
+	field := func(i int) string {
+		if i < len(f) {
+			return f[i]
+		}
+		return ""
+	}
+	atoi := func(i int) int {
+		v, _ := strconv.Atoi(field(i))
+		return v
+	}
+
+	m := &BedrockMOTD{
+		Edition:    field(0),
+		MOTD:       field(1),
+		Protocol:   atoi(2),
+		Version:    field(3),
+		Players:    atoi(4),
+		MaxPlayers: atoi(5),
+		ServerGUID: field(6),
+		LevelName:  field(7),
+		GameMode:   field(8),
+		PortV4:     uint16(atoi(10)),
+		PortV6:     uint16(atoi(11)),
+	}
+	if m.Edition != "MCPE" && m.Edition != "MCEE" {
+		return nil, fmt.Errorf("unknown Bedrock edition %q", m.Edition)
+	}
+	return m, nil
+}
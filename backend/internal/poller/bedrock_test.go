@@ -0,0 +1,51 @@
+package poller
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBedrockMOTD(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    *BedrockMOTD
+		wantErr bool
+	}{
+		{
+			name: "full",
+			in:   "MCPE;§aMy Server;671;1.20.80;3;20;13253860892328930865;Bedrock level;Survival;1;19132;19133;",
+			want: &BedrockMOTD{
+				Edition: "MCPE", MOTD: "§aMy Server", Protocol: 671, Version: "1.20.80",
+				Players: 3, MaxPlayers: 20, ServerGUID: "13253860892328930865",
+				LevelName: "Bedrock level", GameMode: "Survival", PortV4: 19132, PortV6: 19133,
+			},
+		},
+		{
+			name: "legacy six fields",
+			in:   "MCPE;Old server;70;0.14.0;0;10",
+			want: &BedrockMOTD{Edition: "MCPE", MOTD: "Old server", Protocol: 70, Version: "0.14.0", MaxPlayers: 10},
+		},
+		{
+			name: "education edition",
+			in:   "MCEE;Classroom;390;1.14.31;1;30;1;World;Creative;1;;;",
+			want: &BedrockMOTD{
+				Edition: "MCEE", MOTD: "Classroom", Protocol: 390, Version: "1.14.31",
+				Players: 1, MaxPlayers: 30, ServerGUID: "1", LevelName: "World", GameMode: "Creative",
+			},
+		},
+		{name: "too few fields", in: "MCPE;Server;1;1.0", wantErr: true},
+		{name: "unknown edition", in: "JAVA;Server;1;1.0;0;10", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBedrockMOTD(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBedrockMOTD() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parseBedrockMOTD() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
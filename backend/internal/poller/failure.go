@@ -0,0 +1,137 @@
+package poller
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/maintenance"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// offlineConfirmFailures — сколько неудачных опросов подряд нужно, чтобы признать сервер офлайн.
+// Один потерянный UDP-пакет не должен переводить сервер в офлайн и будить дежурных.
+const offlineConfirmFailures = 3
+
+// queryRetryBackoff — паузы между повторными попытками Info внутри одного опроса.
+// Одной повторной попытки хватает на потерянный пакет: долгие сбои подтверждаются
+// серией опросов (offlineConfirmFailures), а не ожиданием внутри одного.
+var queryRetryBackoff = []time.Duration{500 * time.Millisecond}
+
+// Причины неудачного опроса (ServerStatus.FailureReason)
+const (
+	FailureTimeout     = "timeout"
+	FailureRefused     = "refused"
+	FailureUnreachable = "unreachable"
+	FailureDNS         = "dns"
+	FailureParse       = "parse"
+)
+
+// failureState — серия неудачных опросов одного сервера
+type failureState struct {
+	count      int
+	since      time.Time     // время первой неудачи в серии
+	reason     string        // причина последней неудачи
+	alertAfter time.Duration // AlertsConfig.OfflineTimeout
+	alerted    bool          // уведомление об офлайне уже отправлено
+}
+
+// QueryInfo опрашивает сервер протоколом его игры, повторяя попытку после короткой паузы.
+// done прерывает ожидание между попытками (nil — не прерывать).
+func QueryInfo(gameType, ip string, port uint16, done <-chan struct{}) (*models.ServerStatus, error) {
+	proto := ProtocolFor(gameType)
+	status, err := proto.Info(ip, port)
+	for _, wait := range queryRetryBackoff {
+		if err == nil {
+			break
+		}
+		select {
+		case <-time.After(wait):
+		case <-done:
+			return nil, err
+		}
+		status, err = proto.Info(ip, port)
+	}
+	return status, err
+}
+
+// ClassifyFailure сводит ошибку протокола к одной из причин Failure*
+func ClassifyFailure(err error) string {
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr):
+		return FailureDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return FailureRefused
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return FailureUnreachable
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		return FailureTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return FailureTimeout
+	}
+	// Сервер ответил, но ответ не удалось разобрать
+	return FailureParse
+}
+
+// recordFailure учитывает неудачный опрос и решает, пора ли считать сервер офлайн.
+// Пока серия не подтверждена, в БД пишутся только счётчик и причина — остальной статус
+// (онлайн, игроки, сессии) остаётся прежним; recheck — когда опросить сервер снова.
+// Вызывается только из processResults (однопоточно) — мьютекс не нужен.
+func (p *Poller) recordFailure(serverID uint, status *models.ServerStatus) (confirmed bool, recheck time.Duration) {
+	f, ok := p.failures[serverID]
+	if !ok {
+		f = &failureState{since: time.Now()}
+		var cfg models.AlertsConfig
+		if err := database.DB.Select("offline_timeout").Where("server_id = ?", serverID).First(&cfg).Error; err == nil {
+			f.alertAfter = time.Duration(cfg.OfflineTimeout) * time.Minute
+		}
+		p.failures[serverID] = f
+	}
+	f.count++
+	f.reason = status.FailureReason
+	status.FailCount = f.count
+
+	// Уже офлайн — подтверждать нечего
+	if wasOnline, seen := p.prevOnline[serverID]; seen && !wasOnline {
+		return true, 0
+	}
+	recheck = failureRecheckInterval
+	if f.count >= offlineConfirmFailures {
+		if agentQuorumOffline(serverID, f.count == offlineConfirmFailures) {
+			return true, 0
+		}
+		// Агенты в других регионах сервер видят — проблема на нашем маршруте, опрашиваем в обычном темпе
+		recheck = 0
+	}
+	database.DB.Model(&models.ServerStatus{}).Where("server_id = ?", serverID).
+		Updates(map[string]interface{}{"fail_count": f.count, "failure_reason": f.reason})
+	return false, recheck
+}
+
+// offlineAlertDue сообщает, что простой длится дольше AlertsConfig.OfflineTimeout
+// и уведомление ещё не отправлялось. Помечает уведомление отправленным.
+// Во время плановых работ уведомление откладывается: если сервер не поднимется
+// к концу окна, оно уйдёт при первом опросе после него.
+func (p *Poller) offlineAlertDue(serverID uint) bool {
+	f, ok := p.failures[serverID]
+	if !ok || f.alerted {
+		return false
+	}
+	if _, down := p.offlineSince[serverID]; !down {
+		return false // офлайн с момента запуска — переход не наблюдали
+	}
+	if time.Since(f.since) < f.alertAfter {
+		return false
+	}
+	if maintenance.Active(serverID, time.Now()) {
+		return false
+	}
+	f.alerted = true
+	return true
+}
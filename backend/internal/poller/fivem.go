@@ -0,0 +1,168 @@
+package poller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// fivemProtocol — FiveM / RedM (FXServer): HTTP API на игровом порту
+type fivemProtocol struct{}
+
+func (fivemProtocol) Name() string               { return "fivem" }
+func (fivemProtocol) DefaultPort() uint16        { return 30120 }
+func (fivemProtocol) Capabilities() Capabilities { return Capabilities{Players: true, Rules: true} }
+
+func (fivemProtocol) Info(ip string, port uint16) (*models.ServerStatus, error) {
+	return QueryFiveM(ip, port)
+}
+
+func (fivemProtocol) Players(ip string, port uint16) ([]models.ServerPlayer, error) {
+	return QueryFiveMPlayers(ip, port)
+}
+
+// Rules возвращает переменные сервера из info.json (sv_projectName, tags, locale, …) и список ресурсов
+func (fivemProtocol) Rules(ip string, port uint16) (map[string]string, error) {
+	var info fivemInfo
+	if _, err := fivemGet(ip, port, "/info.json", &info); err != nil {
+		return nil, err
+	}
+	rules := make(map[string]string, len(info.Vars)+1)
+	for k, v := range info.Vars {
+		rules[k] = v
+	}
+	if len(info.Resources) > 0 {
+		rules["resources"] = strings.Join(info.Resources, ",")
+	}
+	return rules, nil
+}
+
+func init() {
+	RegisterProtocol(fivemProtocol{})
+	RegisterGame("fivem", "FiveM", "fivem", 0)
+	RegisterGame("redm", "RedM", "fivem", 0)
+}
+
+// fivemHTTPClient — отдельный клиент с таймаутом опроса, а не общим pollerHTTPClient (10 с)
+var fivemHTTPClient = &http.Client{Timeout: udpTimeout}
+
+// fivemMaxBody — info.json крупных серверов со списком ресурсов весит сотни КБ
+const fivemMaxBody = 4 << 20
+
+// fivemColorRegex — цветовые коды в названиях (^1, ^7 …)
+var fivemColorRegex = regexp.MustCompile(`\^[0-9]`)
+
+type fivemDynamic struct {
+	Clients    int    `json:"clients"`
+	GameType   string `json:"gametype"`
+	Hostname   string `json:"hostname"`
+	MapName    string `json:"mapname"`
+	MaxClients string `json:"sv_maxclients"` // строкой
+}
+
+type fivemInfo struct {
+	Icon      string            `json:"icon"` // base64 PNG 96×96
+	Resources []string          `json:"resources"`
+	Server    string            `json:"server"` // "FXServer-master v1.0.0.7290 linux"
+	Vars      map[string]string `json:"vars"`
+}
+
+type fivemPlayer struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Ping        int      `json:"ping"`
+	Identifiers []string `json:"identifiers"`
+}
+
+// fivemGet загружает JSON-эндпоинт сервера и возвращает время ответа
+func fivemGet(ip string, port uint16, path string, out interface{}) (time.Duration, error) {
+	start := time.Now()
+	resp, err := fivemHTTPClient.Get("http://" + hostPort(ip, port) + path) //nolint:noctx
+	if err != nil {
+		return 0, fmt.Errorf("http: %w", err)
+	}
+	defer resp.Body.Close()
+	rtt := time.Since(start)
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%s: status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, fivemMaxBody)).Decode(out); err != nil {
+		return 0, fmt.Errorf("%s: json parse: %w", path, err)
+	}
+	return rtt, nil
+}
+
+// QueryFiveM опрашивает /dynamic.json (онлайн, карта) и /info.json (версия, теги, иконка)
+func QueryFiveM(ip string, port uint16) (*models.ServerStatus, error) {
+	var dyn fivemDynamic
+	rtt, err := fivemGet(ip, port, "/dynamic.json", &dyn)
+	if err != nil {
+		return nil, err
+	}
+	maxClients, _ := strconv.Atoi(dyn.MaxClients)
+
+	status := &models.ServerStatus{
+		OnlineStatus: true,
+		PlayersNow:   dyn.Clients,
+		PlayersMax:   maxClients,
+		CurrentMap:   dyn.MapName,
+		ServerName:   strings.TrimSpace(fivemColorRegex.ReplaceAllString(dyn.Hostname, "")),
+		PingMS:       int(rtt.Milliseconds()),
+	}
+
+	// info.json необязателен: часть серверов закрывает его прокси
+	var info fivemInfo
+	if _, err := fivemGet(ip, port, "/info.json", &info); err == nil {
+		status.Version = truncate(info.Server, 64)
+		status.Keywords = truncate(info.Vars["tags"], 512)
+		if name := info.Vars["sv_projectName"]; name != "" {
+			status.ServerName = strings.TrimSpace(fivemColorRegex.ReplaceAllString(name, ""))
+		}
+		if icon := pngDataURLPrefix + info.Icon; len(info.Icon) <= mcMaxFavicon && IsPNGDataURL(icon) {
+			status.Favicon = icon
+		}
+	}
+	return status, nil
+}
+
+// QueryFiveMPlayers возвращает список игроков из /players.json
+func QueryFiveMPlayers(ip string, port uint16) ([]models.ServerPlayer, error) {
+	var list []fivemPlayer
+	if _, err := fivemGet(ip, port, "/players.json", &list); err != nil {
+		return nil, err
+	}
+	players := make([]models.ServerPlayer, 0, len(list))
+	for _, p := range list {
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			continue
+		}
+		players = append(players, models.ServerPlayer{
+			Name:        name,
+			Ping:        p.Ping,
+			Identifiers: fivemPublicIdentifiers(p.Identifiers),
+		})
+	}
+	return players, nil
+}
+
+// fivemPublicIdentifiers отбрасывает ip: — адрес игрока не должен уходить дальше опросчика
+func fivemPublicIdentifiers(ids []string) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !strings.HasPrefix(id, "ip:") {
+			out = append(out, id)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,33 @@
+package poller
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// openIncident записывает начало простоя при подтверждённом переходе в офлайн.
+// Если инцидент уже открыт (например, до перезапуска поллера), второй не создаётся.
+func openIncident(serverID uint, f *failureState) {
+	var open int64
+	database.DB.Model(&models.ServerIncident{}).
+		Where("server_id = ? AND resolved_at IS NULL", serverID).
+		Count(&open)
+	if open > 0 {
+		return
+	}
+	database.DB.Create(&models.ServerIncident{ServerID: serverID, StartedAt: f.since, Cause: f.reason})
+}
+
+// resolveIncident закрывает открытый инцидент сервера моментом at
+func resolveIncident(serverID uint, at time.Time) {
+	database.DB.Model(&models.ServerIncident{}).
+		Where("server_id = ? AND resolved_at IS NULL", serverID).
+		Updates(map[string]interface{}{
+			"resolved_at":  at,
+			"duration_sec": gorm.Expr("GREATEST(0, TIMESTAMPDIFF(SECOND, started_at, ?))", at),
+		})
+}
@@ -0,0 +1,72 @@
+package poller
+
+import (
+	"sync"
+	"time"
+)
+
+// ProtocolStats — накопленная статистика опросов одного протокола
+type ProtocolStats struct {
+	Polls    uint64  `json:"polls"`
+	Failures uint64  `json:"failures"`
+	AvgMS    float64 `json:"avg_ms"`  // средняя длительность опроса (с повторами)
+	LastMS   int64   `json:"last_ms"` // длительность последнего опроса
+	MaxMS    int64   `json:"max_ms"`
+}
+
+// protocolMetrics собирает длительность опросов по протоколам; пишут все воркеры
+type protocolMetrics struct {
+	mu    sync.Mutex
+	stats map[string]*ProtocolStats
+	total map[string]time.Duration
+}
+
+func newProtocolMetrics() *protocolMetrics {
+	return &protocolMetrics{
+		stats: make(map[string]*ProtocolStats),
+		total: make(map[string]time.Duration),
+	}
+}
+
+func (m *protocolMetrics) observe(protocol string, d time.Duration, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, exists := m.stats[protocol]
+	if !exists {
+		s = &ProtocolStats{}
+		m.stats[protocol] = s
+	}
+	s.Polls++
+	if !ok {
+		s.Failures++
+	}
+	m.total[protocol] += d
+	s.AvgMS = float64(m.total[protocol].Milliseconds()) / float64(s.Polls)
+	s.LastMS = d.Milliseconds()
+	if s.LastMS > s.MaxMS {
+		s.MaxMS = s.LastMS
+	}
+}
+
+func (m *protocolMetrics) snapshot() map[string]ProtocolStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]ProtocolStats, len(m.stats))
+	for name, s := range m.stats {
+		out[name] = *s
+	}
+	return out
+}
+
+// Stats — метрики поллера для админ-панели
+type Stats struct {
+	Scheduler SchedulerStats           `json:"scheduler"`
+	Protocols map[string]ProtocolStats `json:"protocols"`
+}
+
+// Stats возвращает текущее состояние очереди и задержки протоколов
+func (p *Poller) Stats() Stats {
+	s := p.sched.stats()
+	s.JobsBacklog = len(p.jobs)
+	return Stats{Scheduler: s, Protocols: p.metrics.snapshot()}
+}
@@ -2,8 +2,10 @@ package poller
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -14,10 +16,64 @@ import (
 	"github.com/RJ-Bond/js-monitoring/internal/models"
 )
 
-// QueryMinecraft выполняет запрос статуса по протоколу Minecraft 1.7+ (JSON handshake)
+// minecraftProtocol — Minecraft Java Edition Server List Ping (+ UDP Query, если включён)
+type minecraftProtocol struct{}
+
+func (minecraftProtocol) Name() string               { return "minecraft" }
+func (minecraftProtocol) DefaultPort() uint16        { return 25565 }
+func (minecraftProtocol) Capabilities() Capabilities { return Capabilities{Players: true, Rules: true} }
+
+func (minecraftProtocol) Info(ip string, port uint16) (*models.ServerStatus, error) {
+	return QueryMinecraft(ip, port)
+}
+
+func (minecraftProtocol) Players(ip string, port uint16) ([]models.ServerPlayer, error) {
+	return queryMinecraftPlayersFull(ip, port)
+}
+
+// Rules возвращает key-value секцию Query (plugins, map, version, gametype, …)
+func (minecraftProtocol) Rules(ip string, port uint16) (map[string]string, error) {
+	stat, err := queryMinecraftFullStatCached(ip, port)
+	if err != nil {
+		return nil, err
+	}
+	return stat.KV, nil
+}
+
+func init() {
+	RegisterProtocol(minecraftProtocol{})
+	RegisterGame("minecraft", "Minecraft", "minecraft", 0)
+}
+
+// QueryMinecraft выполняет запрос статуса по протоколу Minecraft 1.7+ (JSON handshake).
+// Серверы, не понимающие JSON handshake (до 1.7 и часть модовых), опрашиваются legacy ping.
+// Если соединиться не удалось, legacy ping не пробуется — он упрётся в тот же порт.
 func QueryMinecraft(ip string, port uint16) (*models.ServerStatus, error) {
-	addr := fmt.Sprintf("%s:%d", ip, port)
-	conn, err := net.DialTimeout("tcp", addr, udpTimeout)
+	status, err := queryMinecraftJSON(ip, port)
+	if err == nil {
+		return status, nil
+	}
+	var protoErr *mcProtocolError
+	if !errors.As(err, &protoErr) {
+		return nil, err
+	}
+	if legacy, legacyErr := QueryMinecraftLegacy(ip, port); legacyErr == nil {
+		return legacy, nil
+	}
+	return nil, err
+}
+
+// mcProtocolError — сервер принял TCP-соединение, но не ответил по JSON-протоколу
+type mcProtocolError struct {
+	err error
+}
+
+func (e *mcProtocolError) Error() string { return e.err.Error() }
+func (e *mcProtocolError) Unwrap() error { return e.err }
+
+func queryMinecraftJSON(ip string, port uint16) (*models.ServerStatus, error) {
+	host, dialPort := mcResolveSRV(ip, port)
+	conn, err := net.DialTimeout("tcp", hostPort(host, dialPort), udpTimeout)
 	if err != nil {
 		return nil, fmt.Errorf("connect failed: %w", err)
 	}
@@ -27,16 +83,17 @@ func QueryMinecraft(ip string, port uint16) (*models.ServerStatus, error) {
 
 	start := time.Now()
 
-	if err := mcSendHandshake(conn, ip, port); err != nil {
-		return nil, fmt.Errorf("handshake failed: %w", err)
+	// В handshake — исходное имя хоста: по нему работают прокси (BungeeCord, Velocity) и виртуальные хосты
+	if err := mcSendHandshake(conn, ip, dialPort); err != nil {
+		return nil, &mcProtocolError{fmt.Errorf("handshake failed: %w", err)}
 	}
 	if err := mcSendStatusRequest(conn); err != nil {
-		return nil, fmt.Errorf("status request failed: %w", err)
+		return nil, &mcProtocolError{fmt.Errorf("status request failed: %w", err)}
 	}
 
 	status, err := mcReadStatusResponse(conn)
 	if err != nil {
-		return nil, fmt.Errorf("read response failed: %w", err)
+		return nil, &mcProtocolError{fmt.Errorf("read response failed: %w", err)}
 	}
 
 	status.PingMS = int(time.Since(start).Milliseconds())
@@ -46,15 +103,15 @@ func QueryMinecraft(ip string, port uint16) (*models.ServerStatus, error) {
 
 // QueryMinecraftPlayers возвращает список игроков из players.sample
 func QueryMinecraftPlayers(ip string, port uint16) ([]models.ServerPlayer, error) {
-	addr := fmt.Sprintf("%s:%d", ip, port)
-	conn, err := net.DialTimeout("tcp", addr, udpTimeout)
+	host, dialPort := mcResolveSRV(ip, port)
+	conn, err := net.DialTimeout("tcp", hostPort(host, dialPort), udpTimeout)
 	if err != nil {
 		return nil, fmt.Errorf("connect failed: %w", err)
 	}
 	defer conn.Close()
 	_ = conn.SetDeadline(time.Now().Add(udpTimeout))
 
-	if err := mcSendHandshake(conn, ip, port); err != nil {
+	if err := mcSendHandshake(conn, ip, dialPort); err != nil {
 		return nil, err
 	}
 	if err := mcSendStatusRequest(conn); err != nil {
@@ -151,44 +208,54 @@ type mcStatusJSON struct {
 		Name string `json:"name"`
 	} `json:"version"`
 	Description json.RawMessage `json:"description"`
+	Favicon     string          `json:"favicon"` // data:image/png;base64,…
 }
 
-var mcFormatRegex = regexp.MustCompile(`§.`)
+// mcMaxFavicon — ограничение на размер favicon (64×64 PNG обычно занимает единицы КБ)
+const mcMaxFavicon = 32 << 10
 
-// parseMCDescription извлекает текст из description (строка или объект {"text":"..."})
-func parseMCDescription(raw json.RawMessage) string {
-	if len(raw) == 0 {
-		return ""
-	}
-	// Попытка: plain string
-	var s string
-	if err := json.Unmarshal(raw, &s); err == nil {
-		return strings.TrimSpace(mcFormatRegex.ReplaceAllString(s, ""))
-	}
-	// Попытка: объект с полем text
-	var obj struct {
-		Text string `json:"text"`
-	}
-	if err := json.Unmarshal(raw, &obj); err == nil && obj.Text != "" {
-		return strings.TrimSpace(mcFormatRegex.ReplaceAllString(obj.Text, ""))
+const pngDataURLPrefix = "data:image/png;base64,"
+
+var pngMagic = []byte("\x89PNG\r\n\x1a\n")
+
+// IsPNGDataURL проверяет, что favicon — base64 data URL с PNG внутри. Иконка отдаётся
+// публичным эндпоинтом, поэтому другие типы (SVG с кодом, HTML) не принимаются.
+func IsPNGDataURL(s string) bool {
+	if !strings.HasPrefix(s, pngDataURLPrefix) || len(s) < len(pngDataURLPrefix)+12 {
+		return false
 	}
-	return ""
+	// 12 символов base64 — 9 байт, хватает на сигнатуру PNG
+	head, err := base64.StdEncoding.DecodeString(s[len(pngDataURLPrefix) : len(pngDataURLPrefix)+12])
+	return err == nil && bytes.HasPrefix(head, pngMagic)
 }
 
+// mcMaxStatusJSON — ограничение на размер JSON ответа на status request
+const mcMaxStatusJSON = 64 << 10
+
+var mcFormatRegex = regexp.MustCompile(`§.`)
+
 func mcReadRawJSON(conn net.Conn) ([]byte, error) {
 	// Packet length
-	if _, err := mcReadVarInt(conn); err != nil {
+	packetLen, err := mcReadVarInt(conn)
+	if err != nil {
 		return nil, err
 	}
+	if packetLen <= 0 || packetLen > mcMaxStatusJSON+16 {
+		return nil, fmt.Errorf("invalid packet length %d", packetLen)
+	}
 	// Packet ID
 	if _, err := mcReadVarInt(conn); err != nil {
 		return nil, err
 	}
-	// JSON string length
+	// JSON string length: проверяется до выделения буфера, иначе сервер
+	// заставит выделить до 2 ГБ одним VarInt
 	jsonLen, err := mcReadVarInt(conn)
 	if err != nil {
 		return nil, err
 	}
+	if jsonLen <= 0 || jsonLen > mcMaxStatusJSON || jsonLen >= packetLen {
+		return nil, fmt.Errorf("invalid status length %d", jsonLen)
+	}
 	jsonData := make([]byte, jsonLen)
 	if _, err := io.ReadFull(conn, jsonData); err != nil {
 		return nil, err
@@ -207,12 +274,23 @@ func mcReadStatusResponse(conn net.Conn) (*models.ServerStatus, error) {
 		return nil, fmt.Errorf("json parse: %w", err)
 	}
 
-	return &models.ServerStatus{
+	spans := parseMCMOTDSpans(s.Description)
+	status := &models.ServerStatus{
 		PlayersNow: s.Players.Online,
 		PlayersMax: s.Players.Max,
 		CurrentMap: "world",
-		ServerName: parseMCDescription(s.Description),
-	}, nil
+		ServerName: strings.TrimSpace(mcMOTDText(spans)),
+		Version:    truncate(s.Version.Name, 64),
+	}
+	if len(spans) > 0 {
+		if b, err := json.Marshal(spans); err == nil {
+			status.MOTDSpans = string(b)
+		}
+	}
+	if len(s.Favicon) <= mcMaxFavicon && IsPNGDataURL(s.Favicon) {
+		status.Favicon = s.Favicon
+	}
+	return status, nil
 }
 
 func mcParseFullStatus(conn net.Conn) (*mcStatusJSON, error) {
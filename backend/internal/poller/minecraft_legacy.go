@@ -0,0 +1,119 @@
+package poller
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// mcSRVCacheTTL — сколько хранить результат SRV-запроса: опрос идёт каждые 10–60 секунд
+const mcSRVCacheTTL = 5 * time.Minute
+
+type mcSRVEntry struct {
+	host    string
+	port    uint16
+	expires time.Time
+}
+
+// mcSRVCache: "host:port" → mcSRVEntry
+var mcSRVCache sync.Map
+
+// mcResolveSRV возвращает адрес для подключения с учётом записи _minecraft._tcp.<host>.
+// Как и клиент игры, SRV смотрим только для доменного имени на стандартном порту:
+// явно указанный нестандартный порт важнее DNS. Без записи возвращается исходный адрес.
+func mcResolveSRV(host string, port uint16) (string, uint16) {
+	if net.ParseIP(host) != nil || (port != 0 && port != minecraftProtocol{}.DefaultPort()) {
+		return host, port
+	}
+	key := hostPort(host, port)
+	if v, ok := mcSRVCache.Load(key); ok {
+		if e := v.(mcSRVEntry); time.Now().Before(e.expires) {
+			return e.host, e.port
+		}
+	}
+
+	entry := mcSRVEntry{host: host, port: port, expires: time.Now().Add(mcSRVCacheTTL)}
+	ctx, cancel := context.WithTimeout(context.Background(), udpTimeout)
+	defer cancel()
+	if _, addrs, err := net.DefaultResolver.LookupSRV(ctx, "minecraft", "tcp", host); err == nil && len(addrs) > 0 {
+		entry.host = strings.TrimSuffix(addrs[0].Target, ".")
+		entry.port = addrs[0].Port
+	}
+	mcSRVCache.Store(key, entry)
+	return entry.host, entry.port
+}
+
+// QueryMinecraftLegacy — Server List Ping для серверов до 1.7 (0xFE 0x01).
+// Ответ — пакет 0xFF со строкой UTF-16BE:
+// 1.4+:   "§1\0<protocol>\0<version>\0<motd>\0<online>\0<max>"
+// beta–1.3: "<motd>§<online>§<max>"
+func QueryMinecraftLegacy(ip string, port uint16) (*models.ServerStatus, error) {
+	host, dialPort := mcResolveSRV(ip, port)
+	conn, err := net.DialTimeout("tcp", hostPort(host, dialPort), udpTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("connect failed: %w", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(udpTimeout))
+
+	start := time.Now()
+	if _, err := conn.Write([]byte{0xFE, 0x01}); err != nil {
+		return nil, fmt.Errorf("write: %w", err)
+	}
+
+	header := make([]byte, 3)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+	pingMS := int(time.Since(start).Milliseconds())
+	if header[0] != 0xFF {
+		return nil, fmt.Errorf("invalid legacy response id 0x%02X", header[0])
+	}
+	// Длина — в UTF-16 символах
+	chars := int(binary.BigEndian.Uint16(header[1:3]))
+	if chars == 0 || chars > 1024 {
+		return nil, fmt.Errorf("invalid legacy response length %d", chars)
+	}
+	raw := make([]byte, chars*2)
+	if _, err := io.ReadFull(conn, raw); err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+	units := make([]uint16, chars)
+	for i := range units {
+		units[i] = binary.BigEndian.Uint16(raw[i*2:])
+	}
+	resp := string(utf16.Decode(units))
+
+	status := &models.ServerStatus{OnlineStatus: true, PingMS: pingMS, CurrentMap: "world"}
+	if strings.HasPrefix(resp, "§1\x00") {
+		f := strings.Split(resp, "\x00")
+		if len(f) < 6 {
+			return nil, fmt.Errorf("invalid legacy response")
+		}
+		status.Version = truncate(f[2], 64)
+		status.ServerName = strings.TrimSpace(mcFormatRegex.ReplaceAllString(f[3], ""))
+		status.MOTDSpans = mcMOTDSpansJSON(f[3])
+		status.PlayersNow, _ = strconv.Atoi(f[4])
+		status.PlayersMax, _ = strconv.Atoi(f[5])
+		return status, nil
+	}
+
+	f := strings.Split(resp, "§")
+	if len(f) < 3 {
+		return nil, fmt.Errorf("invalid legacy response")
+	}
+	// MOTD может сам содержать '§' — счётчики всегда в двух последних полях
+	status.ServerName = strings.TrimSpace(strings.Join(f[:len(f)-2], "§"))
+	status.PlayersNow, _ = strconv.Atoi(f[len(f)-2])
+	status.PlayersMax, _ = strconv.Atoi(f[len(f)-1])
+	return status, nil
+}
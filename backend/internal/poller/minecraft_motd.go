@@ -0,0 +1,192 @@
+package poller
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// mcLegacyColors — цвета кодов форматирования §0–§f
+// https://minecraft.wiki/w/Formatting_codes
+var mcLegacyColors = map[rune]string{
+	'0': "black", '1': "dark_blue", '2': "dark_green", '3': "dark_aqua",
+	'4': "dark_red", '5': "dark_purple", '6': "gold", '7': "gray",
+	'8': "dark_gray", '9': "blue", 'a': "green", 'b': "aqua",
+	'c': "red", 'd': "light_purple", 'e': "yellow", 'f': "white",
+}
+
+// mcChatComponent — текстовый компонент чата (description в ответе status ping)
+type mcChatComponent struct {
+	Text          string            `json:"text"`
+	Color         string            `json:"color"`
+	Bold          *bool             `json:"bold"`
+	Italic        *bool             `json:"italic"`
+	Underlined    *bool             `json:"underlined"`
+	Strikethrough *bool             `json:"strikethrough"`
+	Obfuscated    *bool             `json:"obfuscated"`
+	Extra         []json.RawMessage `json:"extra"`
+}
+
+// parseMCMOTDSpans разбирает description (строка, компонент или массив компонентов)
+// в плоский список фрагментов с унаследованным оформлением.
+func parseMCMOTDSpans(raw json.RawMessage) []models.MOTDSpan {
+	var spans []models.MOTDSpan
+	mcAppendComponent(&spans, raw, models.MOTDSpan{}, 0)
+	return mcMergeSpans(spans)
+}
+
+// mcMOTDSpansJSON разбирает текст с кодами '§' (Bedrock, legacy ping) и сериализует фрагменты
+func mcMOTDSpansJSON(text string) string {
+	var spans []models.MOTDSpan
+	mcAppendLegacy(&spans, text, models.MOTDSpan{})
+	if len(spans) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(mcMergeSpans(spans))
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// mcMOTDText склеивает фрагменты в обычный текст
+func mcMOTDText(spans []models.MOTDSpan) string {
+	var b strings.Builder
+	for _, s := range spans {
+		b.WriteString(s.Text)
+	}
+	return b.String()
+}
+
+func mcAppendComponent(spans *[]models.MOTDSpan, raw json.RawMessage, style models.MOTDSpan, depth int) {
+	if len(raw) == 0 || depth > 16 {
+		return
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		mcAppendLegacy(spans, s, style)
+		return
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil {
+		// В массиве первый элемент — родитель остальных
+		if len(list) == 0 {
+			return
+		}
+		parent := mcComponentStyle(list[0], style)
+		mcAppendComponent(spans, list[0], style, depth+1)
+		for _, child := range list[1:] {
+			mcAppendComponent(spans, child, parent, depth+1)
+		}
+		return
+	}
+
+	var c mcChatComponent
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return
+	}
+	style = mcApplyStyle(c, style)
+	mcAppendLegacy(spans, c.Text, style)
+	for _, child := range c.Extra {
+		mcAppendComponent(spans, child, style, depth+1)
+	}
+}
+
+func mcComponentStyle(raw json.RawMessage, style models.MOTDSpan) models.MOTDSpan {
+	var c mcChatComponent
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return style
+	}
+	return mcApplyStyle(c, style)
+}
+
+func mcApplyStyle(c mcChatComponent, style models.MOTDSpan) models.MOTDSpan {
+	style.Text = ""
+	if c.Color != "" {
+		style.Color = c.Color
+	}
+	if c.Bold != nil {
+		style.Bold = *c.Bold
+	}
+	if c.Italic != nil {
+		style.Italic = *c.Italic
+	}
+	if c.Underlined != nil {
+		style.Underlined = *c.Underlined
+	}
+	if c.Strikethrough != nil {
+		style.Strikethrough = *c.Strikethrough
+	}
+	if c.Obfuscated != nil {
+		style.Obfuscated = *c.Obfuscated
+	}
+	return style
+}
+
+// mcAppendLegacy разбивает текст с кодами '§' на фрагменты.
+// Код цвета сбрасывает стили, §r возвращает оформление родительского компонента.
+func mcAppendLegacy(spans *[]models.MOTDSpan, text string, base models.MOTDSpan) {
+	cur := base
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			span := cur
+			span.Text = b.String()
+			*spans = append(*spans, span)
+			b.Reset()
+		}
+	}
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '§' || i+1 >= len(runes) {
+			b.WriteRune(runes[i])
+			continue
+		}
+		code := runes[i+1]
+		if code >= 'A' && code <= 'Z' {
+			code += 'a' - 'A'
+		}
+		i++
+		flush()
+		if color, ok := mcLegacyColors[code]; ok {
+			cur = models.MOTDSpan{Color: color}
+			continue
+		}
+		switch code {
+		case 'k':
+			cur.Obfuscated = true
+		case 'l':
+			cur.Bold = true
+		case 'm':
+			cur.Strikethrough = true
+		case 'n':
+			cur.Underlined = true
+		case 'o':
+			cur.Italic = true
+		case 'r':
+			cur = base
+		}
+	}
+	flush()
+}
+
+// mcMergeSpans склеивает соседние фрагменты с одинаковым оформлением
+func mcMergeSpans(spans []models.MOTDSpan) []models.MOTDSpan {
+	out := make([]models.MOTDSpan, 0, len(spans))
+	for _, s := range spans {
+		if n := len(out); n > 0 {
+			last, probe := out[n-1], s
+			last.Text, probe.Text = "", ""
+			if last == probe {
+				out[n-1].Text += s.Text
+				continue
+			}
+		}
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+package poller
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+func TestParseMCMOTDSpans(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []models.MOTDSpan
+	}{
+		{
+			name: "plain string",
+			in:   `"A Minecraft Server"`,
+			want: []models.MOTDSpan{{Text: "A Minecraft Server"}},
+		},
+		{
+			name: "legacy codes in string",
+			in:   `"§aGreen §lbold§r plain"`,
+			want: []models.MOTDSpan{
+				{Text: "Green ", Color: "green"},
+				{Text: "bold", Color: "green", Bold: true},
+				{Text: " plain"},
+			},
+		},
+		{
+			name: "color code resets formatting",
+			in:   `"§l§cRed"`,
+			want: []models.MOTDSpan{{Text: "Red", Color: "red"}},
+		},
+		{
+			name: "uppercase code",
+			in:   `"§BAqua"`,
+			want: []models.MOTDSpan{{Text: "Aqua", Color: "aqua"}},
+		},
+		{
+			name: "component with inherited extra",
+			in:   `{"text":"Hello ","color":"gold","bold":true,"extra":[{"text":"world","bold":false},{"text":"!"}]}`,
+			want: []models.MOTDSpan{
+				{Text: "Hello ", Color: "gold", Bold: true},
+				{Text: "world", Color: "gold"},
+				{Text: "!", Color: "gold", Bold: true},
+			},
+		},
+		{
+			name: "array: first element is the parent",
+			in:   `[{"text":"A","color":"red"},{"text":"B"},{"text":"C","color":"blue"}]`,
+			want: []models.MOTDSpan{
+				{Text: "AB", Color: "red"},
+				{Text: "C", Color: "blue"},
+			},
+		},
+		{
+			name: "reset returns to component style",
+			in:   `{"text":"§lX§rY","color":"gray"}`,
+			want: []models.MOTDSpan{
+				{Text: "X", Color: "gray", Bold: true},
+				{Text: "Y", Color: "gray"},
+			},
+		},
+		{
+			name: "adjacent equal spans merge",
+			in:   `{"text":"","extra":["foo","bar"]}`,
+			want: []models.MOTDSpan{{Text: "foobar"}},
+		},
+		{
+			name: "trailing section sign kept",
+			in:   `"50%§"`,
+			want: []models.MOTDSpan{{Text: "50%§"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseMCMOTDSpans(json.RawMessage(tt.in))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parseMCMOTDSpans() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCMOTDSpansJSON(t *testing.T) {
+	if got := mcMOTDSpansJSON(""); got != "" {
+		t.Errorf("mcMOTDSpansJSON(\"\") = %q, want empty", got)
+	}
+	got := mcMOTDSpansJSON("§6Gold§r text")
+	want := `[{"text":"Gold","color":"gold"},{"text":" text"}]`
+	if got != want {
+		t.Errorf("mcMOTDSpansJSON() = %s, want %s", got, want)
+	}
+}
+
+func TestMCMOTDText(t *testing.T) {
+	spans := parseMCMOTDSpans(json.RawMessage(`{"text":"§aHello ","extra":[{"text":"world","bold":true}]}`))
+	if got := mcMOTDText(spans); got != "Hello world" {
+		t.Errorf("mcMOTDText() = %q, want %q", got, "Hello world")
+	}
+}
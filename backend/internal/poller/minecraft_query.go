@@ -0,0 +1,164 @@
+package poller
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// Minecraft Query (enable-query=true в server.properties) — UDP-протокол на базе GameSpy4.
+// В отличие от status ping возвращает полный список игроков, плагины и имя мира.
+// https://wiki.vg/Query
+const (
+	mcQueryHandshake = 0x09
+	mcQueryStat      = 0x00
+)
+
+var (
+	mcQueryMagic = []byte{0xFE, 0xFD}
+	// Заголовки секций ответа full stat
+	mcQueryKVHeader      = []byte("splitnum\x00\x80\x00")
+	mcQueryPlayersHeader = []byte("\x01player_\x00\x00")
+)
+
+// mcQueryRetryAfter — сколько не пытаться Query после неудачи (enable-query выключен,
+// UDP-порт закрыт): иначе каждый опрос игроков ждал бы полный таймаут.
+const mcQueryRetryAfter = 10 * time.Minute
+
+// mcQueryFailedAt: адрес → время последней неудачной попытки Query
+var mcQueryFailedAt sync.Map
+
+// MinecraftFullStat — ответ full stat
+type MinecraftFullStat struct {
+	KV      map[string]string // hostname, gametype, version, plugins, map, numplayers, maxplayers, …
+	Players []string
+}
+
+// QueryMinecraftFullStat выполняет handshake и запрос full stat по UDP
+func QueryMinecraftFullStat(ip string, port uint16) (*MinecraftFullStat, error) {
+	conn, err := net.DialTimeout("udp", hostPort(ip, port), udpTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("udp dial: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(udpTimeout)) //nolint:errcheck
+
+	// Сервер учитывает только младшие 4 бита каждого байта session ID
+	sessionID := int32(time.Now().UnixNano()) & 0x0F0F0F0F
+
+	token, err := mcQueryChallenge(conn, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var req bytes.Buffer
+	req.Write(mcQueryMagic)
+	req.WriteByte(mcQueryStat)
+	binary.Write(&req, binary.BigEndian, sessionID) //nolint:errcheck
+	binary.Write(&req, binary.BigEndian, token)     //nolint:errcheck
+	req.Write([]byte{0, 0, 0, 0})                   // 4 байта паддинга — признак full stat
+	if _, err := conn.Write(req.Bytes()); err != nil {
+		return nil, fmt.Errorf("write: %w", err)
+	}
+
+	resp := make([]byte, 8192)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+	if n < 5 || resp[0] != mcQueryStat {
+		return nil, fmt.Errorf("invalid query response (len=%d)", n)
+	}
+	return parseMCFullStat(resp[5:n])
+}
+
+// mcQueryChallenge получает challenge token (действителен ~30 секунд)
+func mcQueryChallenge(conn net.Conn, sessionID int32) (int32, error) {
+	var req bytes.Buffer
+	req.Write(mcQueryMagic)
+	req.WriteByte(mcQueryHandshake)
+	binary.Write(&req, binary.BigEndian, sessionID) //nolint:errcheck
+	if _, err := conn.Write(req.Bytes()); err != nil {
+		return 0, fmt.Errorf("write handshake: %w", err)
+	}
+
+	resp := make([]byte, 64)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return 0, fmt.Errorf("read handshake: %w", err)
+	}
+	if n < 6 || resp[0] != mcQueryHandshake {
+		return 0, fmt.Errorf("invalid handshake response (len=%d)", n)
+	}
+	// Токен приходит ASCII-строкой с нулевым терминатором
+	token, err := strconv.ParseInt(string(bytes.TrimRight(resp[5:n], "\x00")), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid challenge token: %w", err)
+	}
+	return int32(token), nil
+}
+
+func parseMCFullStat(data []byte) (*MinecraftFullStat, error) {
+	if !bytes.HasPrefix(data, mcQueryKVHeader) {
+		return nil, fmt.Errorf("invalid full stat header")
+	}
+	r := bytes.NewReader(data[len(mcQueryKVHeader):])
+
+	stat := &MinecraftFullStat{KV: make(map[string]string)}
+	for r.Len() > 0 {
+		key := readNullString(r)
+		if key == "" {
+			break
+		}
+		stat.KV[key] = readNullString(r)
+	}
+
+	header := make([]byte, len(mcQueryPlayersHeader))
+	if _, err := r.Read(header); err != nil || !bytes.Equal(header, mcQueryPlayersHeader) {
+		return stat, nil // секция игроков отсутствует — не ошибка
+	}
+	for r.Len() > 0 {
+		name := strings.TrimSpace(readNullString(r))
+		if name == "" {
+			break
+		}
+		stat.Players = append(stat.Players, name)
+	}
+	return stat, nil
+}
+
+// queryMinecraftPlayersFull пробует Query, а при выключенном enable-query — players.sample из status ping
+func queryMinecraftPlayersFull(ip string, port uint16) ([]models.ServerPlayer, error) {
+	stat, err := queryMinecraftFullStatCached(ip, port)
+	if err != nil {
+		return QueryMinecraftPlayers(ip, port)
+	}
+	players := make([]models.ServerPlayer, 0, len(stat.Players))
+	for _, name := range stat.Players {
+		players = append(players, models.ServerPlayer{Name: name})
+	}
+	return players, nil
+}
+
+// queryMinecraftFullStatCached — QueryMinecraftFullStat, пропускающий недавно отказавшие адреса
+func queryMinecraftFullStatCached(ip string, port uint16) (*MinecraftFullStat, error) {
+	host, port := mcResolveSRV(ip, port)
+	addr := hostPort(host, port)
+	if v, ok := mcQueryFailedAt.Load(addr); ok && time.Since(v.(time.Time)) < mcQueryRetryAfter {
+		return nil, ErrNotSupported
+	}
+	stat, err := QueryMinecraftFullStat(host, port)
+	if err != nil {
+		mcQueryFailedAt.Store(addr, time.Now())
+		return nil, err
+	}
+	mcQueryFailedAt.Delete(addr)
+	return stat, nil
+}
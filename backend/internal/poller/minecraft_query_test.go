@@ -0,0 +1,75 @@
+package poller
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMCFullStat(t *testing.T) {
+	kv := "splitnum\x00\x80\x00" +
+		"hostname\x00A Minecraft Server\x00gametype\x00SMP\x00version\x001.20.4\x00" +
+		"plugins\x00Paper on 1.20.4: EssentialsX 2.20\x00map\x00world\x00" +
+		"numplayers\x002\x00maxplayers\x0020\x00\x00"
+	wantKV := map[string]string{
+		"hostname":   "A Minecraft Server",
+		"gametype":   "SMP",
+		"version":    "1.20.4",
+		"plugins":    "Paper on 1.20.4: EssentialsX 2.20",
+		"map":        "world",
+		"numplayers": "2",
+		"maxplayers": "20",
+	}
+
+	tests := []struct {
+		name        string
+		in          string
+		wantKV      map[string]string
+		wantPlayers []string
+		wantErr     bool
+	}{
+		{
+			name:        "with players",
+			in:          kv + "\x01player_\x00\x00Notch\x00 jeb_ \x00\x00",
+			wantKV:      wantKV,
+			wantPlayers: []string{"Notch", "jeb_"},
+		},
+		{
+			name:   "empty player list",
+			in:     kv + "\x01player_\x00\x00\x00",
+			wantKV: wantKV,
+		},
+		{
+			name:   "no player section",
+			in:     kv,
+			wantKV: wantKV,
+		},
+		{
+			name:        "truncated player list",
+			in:          kv + "\x01player_\x00\x00Notch",
+			wantKV:      wantKV,
+			wantPlayers: []string{"Notch"},
+		},
+		{
+			name:    "bad header",
+			in:      "hostname\x00x\x00",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMCFullStat([]byte(tt.in))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMCFullStat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.KV, tt.wantKV) {
+				t.Errorf("KV = %v, want %v", got.KV, tt.wantKV)
+			}
+			if !reflect.DeepEqual(got.Players, tt.wantPlayers) {
+				t.Errorf("Players = %q, want %q", got.Players, tt.wantPlayers)
+			}
+		})
+	}
+}
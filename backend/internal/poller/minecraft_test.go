@@ -0,0 +1,92 @@
+package poller
+
+import (
+	"encoding/base64"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestQueryMinecraftJSONErrors(t *testing.T) {
+	// Порт закрыт — ошибка соединения, legacy ping пробовать незачем
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().(*net.TCPAddr)
+	ln.Close()
+
+	var protoErr *mcProtocolError
+	if _, err := queryMinecraftJSON("127.0.0.1", uint16(addr.Port)); err == nil || errors.As(err, &protoErr) {
+		t.Fatalf("closed port: err = %v, want a connect error", err)
+	}
+
+	// Соединение принято и сразу закрыто — сервер не понял JSON handshake
+	ln, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	port := uint16(ln.Addr().(*net.TCPAddr).Port)
+	if _, err := queryMinecraftJSON("127.0.0.1", port); !errors.As(err, &protoErr) {
+		t.Fatalf("handshake rejected: err = %v, want *mcProtocolError", err)
+	}
+}
+
+func TestMCReadRawJSONBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{"valid", []byte{0x05, 0x00, 0x03, '{', '}', ' '}, false},
+		{"zero json length", []byte{0x05, 0x00, 0x00}, true},
+		{"json length beyond packet", []byte{0x03, 0x00, 0x7F}, true},
+		{"huge json length", []byte{0x7F, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0x07}, true},
+		{"huge packet length", []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x07}, true},
+		{"negative packet length", []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x0F}, true},
+	}
+	for _, tt := range tests {
+		server, client := net.Pipe()
+		go func(data []byte) {
+			defer server.Close()
+			_, _ = server.Write(data)
+		}(tt.data)
+		got, err := mcReadRawJSON(client)
+		client.Close()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: mcReadRawJSON() = %q, %v; wantErr %v", tt.name, got, err, tt.wantErr)
+		}
+	}
+}
+
+func TestIsPNGDataURL(t *testing.T) {
+	png := base64.StdEncoding.EncodeToString(append(pngMagic, "\x00\x00\x00\rIHDR"...))
+	svg := base64.StdEncoding.EncodeToString([]byte("<svg onload=alert(1)></svg>"))
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{"png", "data:image/png;base64," + png, true},
+		{"svg declared as svg", "data:image/svg+xml;base64," + svg, false},
+		{"svg declared as png", "data:image/png;base64," + svg, false},
+		{"png declared as other type", "data:text/html;base64," + png, false},
+		{"too short", "data:image/png;base64,iVBO", false},
+		{"not base64", "data:image/png;base64,!!!!!!!!!!!!!!!!", false},
+	}
+	for _, tt := range tests {
+		if got := IsPNGDataURL(tt.data); got != tt.want {
+			t.Errorf("%s: IsPNGDataURL() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
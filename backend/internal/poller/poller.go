@@ -2,17 +2,25 @@ package poller
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"html"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/history"
+	"github.com/RJ-Bond/js-monitoring/internal/maintenance"
 	"github.com/RJ-Bond/js-monitoring/internal/models"
 	"github.com/RJ-Bond/js-monitoring/internal/notify"
 	"gorm.io/gorm"
@@ -23,10 +31,13 @@ const (
 	workerCount        = 50
 	emptyPollInterval  = 60 * time.Second
 	activePollInterval = 10 * time.Second
-	schedulerTick      = 5 * time.Second
 	historyFlushTick   = 30 * time.Second
+	historyRollupTick  = 5 * time.Minute
 	batchSize          = 100
 	discordWorkerTick  = 1 * time.Minute
+	rulesPollInterval  = 5 * time.Minute
+	// notifyDrainTimeout — сколько при остановке ждать неотправленные уведомления
+	notifyDrainTimeout = 5 * time.Second
 )
 
 // Shared HTTP client for all outbound requests (Telegram, Discord, etc.)
@@ -47,15 +58,20 @@ type pollJob struct {
 type pollResult struct {
 	serverID uint
 	status   *models.ServerStatus
-	players  []string // имена игроков на момент опроса (nil если не поддерживается)
+	players  []models.ServerPlayer // игроки на момент опроса (nil если не поддерживается)
+	rules    map[string]string     // отслеживаемые правила (nil если не запрашивались)
 }
 
 // Poller — конкурентный опросчик серверов через Worker Pool
 type Poller struct {
 	jobs    chan pollJob
 	results chan pollResult
-	done    chan struct{}
-	wg      sync.WaitGroup
+	done    <-chan struct{} // закрывается при отмене контекста Start
+	wg      sync.WaitGroup  // воркеры
+	loops   sync.WaitGroup  // фоновые циклы: планировщик, история, Discord, очистка
+	notifs  sync.WaitGroup  // уведомления об офлайне/онлайне в процессе отправки
+	drained chan struct{}   // processResults обработал все результаты
+	stopped chan struct{}   // остановка завершена (см. Wait)
 
 	historyBuf []models.PlayerHistory
 	historyMu  sync.Mutex
@@ -72,10 +88,38 @@ type Poller struct {
 	// Доступ только из processResults — мьютекс не нужен.
 	offlineSince map[uint]time.Time
 
+	// lastVersion хранит последнюю известную версию сервера для детекции обновлений.
+	// Доступ только из processResults — мьютекс не нужен.
+	lastVersion map[uint]string
+
+	// faviconHash хранит хеш сохранённой иконки сервера, чтобы не перезаписывать её каждый опрос.
+	// Доступ только из processResults — мьютекс не нужен.
+	faviconHash map[uint]string
+
+	// failures хранит текущую серию неудачных опросов по серверу (см. recordFailure).
+	// Доступ только из processResults — мьютекс не нужен.
+	failures map[uint]*failureState
+
+	// lastPlayers хранит число игроков из последнего принятого статуса: пока неудача не
+	// подтверждена, интервал опроса выбирается по нему, а не как для пустого сервера.
+	// Доступ только из processResults — мьютекс не нужен.
+	lastPlayers map[uint]int
+
+	// alerts — правила оповещения и их состояние между опросами (см. evaluateAlertRules)
+	alerts *alertRules
+
 	// discordLastSent хранит время последней отправки Discord-embed по serverID.
 	// Доступ только из discordWorker — мьютекс не нужен.
 	discordLastSent map[uint]time.Time
 
+	// rulesPolledAt хранит время последнего A2S_RULES запроса — правила меняются редко,
+	// а ответ большой, поэтому запрашиваем их не чаще rulesPollInterval.
+	rulesPolledAt map[uint]time.Time
+	rulesMu       sync.Mutex
+
+	sched   *scheduleQueue
+	metrics *protocolMetrics
+
 	OnUpdate func(serverID uint, status *models.ServerStatus)
 }
 
@@ -83,17 +127,28 @@ func New(onUpdate func(serverID uint, status *models.ServerStatus)) *Poller {
 	return &Poller{
 		jobs:            make(chan pollJob, 2000),
 		results:         make(chan pollResult, 2000),
-		done:            make(chan struct{}),
+		drained:         make(chan struct{}),
+		stopped:         make(chan struct{}),
 		playerState:     make(map[uint]map[string]time.Time),
 		prevOnline:      make(map[uint]bool),
 		offlineSince:    make(map[uint]time.Time),
+		lastVersion:     make(map[uint]string),
+		faviconHash:     make(map[uint]string),
+		failures:        make(map[uint]*failureState),
+		lastPlayers:     make(map[uint]int),
+		alerts:          newAlertRules(),
 		discordLastSent: make(map[uint]time.Time),
+		rulesPolledAt:   make(map[uint]time.Time),
+		sched:           newScheduleQueue(),
+		metrics:         newProtocolMetrics(),
 		OnUpdate:        onUpdate,
 	}
 }
 
-// Start запускает воркеры, обработчик результатов и планировщик
-func (p *Poller) Start() {
+// Start запускает воркеры, обработчик результатов и планировщик.
+// Поллер работает до отмены ctx, после чего останавливается сам (см. shutdown и Wait).
+func (p *Poller) Start(ctx context.Context) {
+	p.done = ctx.Done()
 	p.closeOrphanSessions()
 	p.purgeScheduledDeletions() // run once on startup
 	for i := 0; i < workerCount; i++ {
@@ -101,10 +156,14 @@ func (p *Poller) Start() {
 		go p.worker()
 	}
 	go p.processResults()
-	go p.batchHistoryWriter()
-	go p.scheduler()
-	go p.discordWorker()
-	go p.accountCleanupWorker()
+	for _, loop := range []func(){p.batchHistoryWriter, p.historyRollupWorker, p.scheduler, p.discordWorker, p.accountCleanupWorker} {
+		p.loops.Add(1)
+		go func(run func()) {
+			defer p.loops.Done()
+			run()
+		}(loop)
+	}
+	go p.shutdown()
 
 	log.Printf("[Poller] started with %d workers", workerCount)
 }
@@ -131,6 +190,10 @@ func (p *Poller) purgeScheduledDeletions() {
 		return
 	}
 	for _, u := range users {
+		// Выданные пользователю доступы к RCON и доступы к его серверам
+		database.DB.Where("user_id = ? OR server_id IN (?)", u.ID,
+			database.DB.Model(&models.Server{}).Select("id").Where("owner_id = ?", u.ID)).
+			Delete(&models.RCONAccess{})
 		database.DB.Where("owner_id = ?", u.ID).Delete(&models.Server{})
 		if err := database.DB.Delete(&models.User{}, u.ID).Error; err == nil {
 			log.Printf("[cleanup] Deleted account %q (ID=%d) — grace period expired", u.Username, u.ID)
@@ -138,12 +201,46 @@ func (p *Poller) purgeScheduledDeletions() {
 	}
 }
 
-// Stop корректно завершает поллер
-func (p *Poller) Stop() {
-	close(p.done)
+// shutdown дожидается отмены контекста и останавливает конвейер по порядку: воркеры,
+// обработка уже полученных результатов, фоновые циклы, уведомления (не дольше
+// notifyDrainTimeout) и последний сброс истории.
+func (p *Poller) shutdown() {
+	<-p.done
+	log.Println("[Poller] stopping")
+
 	p.wg.Wait()
+	close(p.results)
+	<-p.drained
+	p.loops.Wait()
+
+	notified := make(chan struct{})
+	go func() {
+		p.notifs.Wait()
+		close(notified)
+	}()
+	select {
+	case <-notified:
+	case <-time.After(notifyDrainTimeout):
+		log.Printf("[Poller] gave up waiting for pending notifications after %v", notifyDrainTimeout)
+	}
+
 	p.flushHistoryBuffer()
 	log.Println("[Poller] stopped")
+	close(p.stopped)
+}
+
+// Wait блокируется до полной остановки поллера после отмены контекста Start
+func (p *Poller) Wait() {
+	<-p.stopped
+}
+
+// notify отправляет уведомление в фоне; остановка поллера дожидается его отправки
+func (p *Poller) notify(send func()) {
+	p.notifs.Add(1)
+	go func() {
+		defer p.notifs.Done()
+		send()
+	}()
 }
 
 // worker — один воркер из пула; берёт задание из канала и опрашивает сервер
@@ -153,39 +250,43 @@ func (p *Poller) worker() {
 		select {
 		case job := <-p.jobs:
 			status := p.query(&job.server)
-			var players []string
+			if p.stopping() {
+				continue // опрос мог быть прерван остановкой — неудачу за падение не считаем
+			}
+			var players []models.ServerPlayer
 			if status.OnlineStatus && status.PlayersNow > 0 {
-				players = p.queryPlayers(&job.server)
+				players = dropBots(p.queryPlayers(&job.server), status.PlayersNow, status.Bots)
+			}
+			var rules map[string]string
+			if status.OnlineStatus {
+				rules = p.queryRules(&job.server)
 			}
-			p.results <- pollResult{serverID: job.server.ID, status: status, players: players}
+			p.results <- pollResult{serverID: job.server.ID, status: status, players: players, rules: rules}
 		case <-p.done:
 			return
 		}
 	}
 }
 
-// query выбирает протокол и возвращает результат опроса
-func (p *Poller) query(srv *models.Server) *models.ServerStatus {
-	var status *models.ServerStatus
-	var err error
-
-	switch srv.GameType {
-	case "minecraft":
-		status, err = QueryMinecraft(srv.IP, srv.Port)
-	case "samp":
-		status, err = QuerySAMP(srv.IP, srv.Port)
-	case "source", "fivem", "gmod", "valheim", "dayz", "squad", "arma3", "rust", "vrising", "icarus":
-		status, err = QuerySource(srv.IP, srv.Port)
+// stopping сообщает, что контекст поллера отменён
+func (p *Poller) stopping() bool {
+	select {
+	case <-p.done:
+		return true
 	default:
-		// minecraft_bedrock, terraria и другие — пробуем Source как fallback
-		status, err = QuerySource(srv.IP, srv.Port)
+		return false
 	}
+}
 
+// query выбирает протокол по game_type и возвращает результат опроса
+func (p *Poller) query(srv *models.Server) *models.ServerStatus {
+	status, err := p.queryInfo(srv)
 	if err != nil {
 		return &models.ServerStatus{
-			ServerID:     srv.ID,
-			OnlineStatus: false,
-			LastUpdate:   time.Now(),
+			ServerID:      srv.ID,
+			OnlineStatus:  false,
+			LastUpdate:    time.Now(),
+			FailureReason: ClassifyFailure(err),
 		}
 	}
 
@@ -194,96 +295,243 @@ func (p *Poller) query(srv *models.Server) *models.ServerStatus {
 	return status
 }
 
-// queryPlayers запрашивает список игроков по имени (для session tracking)
-func (p *Poller) queryPlayers(srv *models.Server) []string {
-	var serverPlayers []models.ServerPlayer
-	var err error
+// queryInfo запрашивает информацию о сервере и учитывает длительность в метриках протокола
+func (p *Poller) queryInfo(srv *models.Server) (*models.ServerStatus, error) {
+	start := time.Now()
+	status, err := QueryInfo(srv.GameType, srv.IP, srv.QueryAddrPort(), p.done)
+	p.metrics.observe(ProtocolFor(srv.GameType).Name(), time.Since(start), err == nil)
+	return status, err
+}
 
-	switch srv.GameType {
-	case "source", "fivem", "gmod", "valheim", "dayz", "squad", "vrising", "terraria", "icarus":
-		serverPlayers, err = QuerySourcePlayers(srv.IP, srv.Port)
-	case "samp":
-		serverPlayers, err = QuerySAMPPlayers(srv.IP, srv.Port)
-	case "minecraft", "minecraft_bedrock":
-		serverPlayers, err = QueryMinecraftPlayers(srv.IP, srv.Port)
-	default:
+// queryPlayers запрашивает список игроков (для session tracking)
+func (p *Poller) queryPlayers(srv *models.Server) []models.ServerPlayer {
+	proto := ProtocolFor(srv.GameType)
+	if !proto.Capabilities().Players {
 		return nil
 	}
 
+	serverPlayers, err := proto.Players(srv.IP, srv.QueryAddrPort())
 	if err != nil || len(serverPlayers) == 0 {
 		return nil
 	}
+	return serverPlayers
+}
+
+// dropBots убирает ботов из списка игроков, чтобы они не попадали в сессии и лидерборды.
+// A2S_PLAYER не отличает ботов от людей, но боты заходят при загрузке карты — лишними
+// считаются записи, которые дольше всех на сервере. humans — число людей из A2S_INFO.
+func dropBots(players []models.ServerPlayer, humans, bots int) []models.ServerPlayer {
+	extra := len(players) - humans
+	if extra > bots {
+		extra = bots
+	}
+	if extra <= 0 {
+		return players
+	}
+	sorted := append([]models.ServerPlayer(nil), players...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Duration < sorted[j].Duration })
+	return sorted[:len(sorted)-extra]
+}
+
+// queryRules запрашивает отслеживаемые правила сервера (не чаще rulesPollInterval)
+func (p *Poller) queryRules(srv *models.Server) map[string]string {
+	keys := srv.TrackedRuleKeys()
+	if len(keys) == 0 {
+		return nil
+	}
+	proto := ProtocolFor(srv.GameType)
+	if !proto.Capabilities().Rules {
+		return nil
+	}
 
-	names := make([]string, 0, len(serverPlayers))
-	for _, sp := range serverPlayers {
-		if sp.Name != "" {
-			names = append(names, sp.Name)
+	p.rulesMu.Lock()
+	last, ok := p.rulesPolledAt[srv.ID]
+	due := !ok || time.Since(last) >= rulesPollInterval
+	if due {
+		p.rulesPolledAt[srv.ID] = time.Now()
+	}
+	p.rulesMu.Unlock()
+	if !due {
+		return nil
+	}
+
+	all, err := proto.Rules(srv.IP, srv.QueryAddrPort())
+	if err != nil {
+		return nil
+	}
+	rules := make(map[string]string, len(keys))
+	for _, k := range keys {
+		if v, ok := all[k]; ok {
+			rules[k] = truncate(v, 512)
 		}
 	}
-	return names
+	return rules
+}
+
+// saveRules сохраняет отслеживаемые правила и удаляет те, что сервер больше не сообщает
+func (p *Poller) saveRules(serverID uint, rules map[string]string) {
+	keys := make([]string, 0, len(rules))
+	for k, v := range rules {
+		keys = append(keys, k)
+		database.DB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "server_id"}, {Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).Create(&models.ServerRule{ServerID: serverID, Key: k, Value: v})
+	}
+	q := database.DB.Where("server_id = ?", serverID)
+	if len(keys) > 0 {
+		q = q.Where("`key` NOT IN ?", keys)
+	}
+	q.Delete(&models.ServerRule{})
 }
 
-// processResults сохраняет статус в БД и уведомляет WebSocket клиентов
+// processResults сохраняет статус в БД и уведомляет WebSocket клиентов.
+// Работает, пока shutdown не закроет канал results, затем закрывает открытые сессии игроков.
 func (p *Poller) processResults() {
-	for {
-		select {
-		case res := <-p.results:
-			if res.status == nil {
+	defer close(p.drained)
+	for res := range p.results {
+		if res.status == nil {
+			p.sched.complete(res.serverID, 0, 0)
+			continue
+		}
+		// Неподтверждённая неудача — сервер пока считается в прежнем состоянии, перепроверяем скоро
+		if !res.status.OnlineStatus {
+			if confirmed, recheck := p.recordFailure(res.serverID, res.status); !confirmed {
+				p.sched.complete(res.serverID, p.lastPlayers[res.serverID], recheck)
 				continue
 			}
+		}
 
-			// Upsert по server_id: INSERT при первом опросе, UPDATE при последующих
-			database.DB.Clauses(clause.OnConflict{
-				Columns: []clause.Column{{Name: "server_id"}},
-				DoUpdates: clause.AssignmentColumns([]string{
-					"online_status", "players_now", "players_max",
-					"current_map", "server_name", "ping_ms", "last_update",
-				}),
-			}).Create(res.status)
-
-			// Добавить в буфер истории
-			p.historyMu.Lock()
-			p.historyBuf = append(p.historyBuf, models.PlayerHistory{
-				ServerID:  res.serverID,
-				Count:     res.status.PlayersNow,
-				IsOnline:  res.status.OnlineStatus,
-				PingMS:    res.status.PingMS,
-				Timestamp: time.Now(),
-			})
-			p.historyMu.Unlock()
-
-			// Отслеживать алерты при переходах online↔offline
-			wasOnline, seen := p.prevOnline[res.serverID]
-			isOnline := res.status.OnlineStatus
-			if seen {
-				if wasOnline && !isOnline {
-					p.offlineSince[res.serverID] = time.Now()
-					go p.sendOfflineAlert(res.serverID)
-				} else if !wasOnline && isOnline {
-					since := p.offlineSince[res.serverID]
-					delete(p.offlineSince, res.serverID)
-					go p.sendOnlineAlert(res.serverID, since)
+		versionChanged := p.detectVersionChange(res.serverID, res.status)
+		p.evaluateAlertRules(res.serverID, res.status, versionChanged)
+
+		// Upsert по server_id: INSERT при первом опросе, UPDATE при последующих
+		database.DB.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "server_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"online_status", "players_now", "players_max",
+				"current_map", "server_name", "ping_ms", "last_update",
+				"bots", "server_type", "environment", "password", "vac",
+				"version", "game_port", "steam_id", "keywords", "app_id",
+				"motd_spans", "fail_count", "failure_reason",
+			}),
+		}).Create(res.status)
+		if versionChanged {
+			database.DB.Model(&models.ServerStatus{}).Where("server_id = ?", res.serverID).
+				Update("version_changed_at", res.status.VersionChangedAt)
+		}
+
+		// Добавить в буфер истории
+		p.historyMu.Lock()
+		p.historyBuf = append(p.historyBuf, models.PlayerHistory{
+			ServerID:  res.serverID,
+			Count:     res.status.PlayersNow,
+			IsOnline:  res.status.OnlineStatus,
+			PingMS:    res.status.PingMS,
+			Timestamp: time.Now(),
+		})
+		p.historyMu.Unlock()
+
+		// Отслеживать алерты и инциденты при переходах online↔offline
+		wasOnline, seen := p.prevOnline[res.serverID]
+		isOnline := res.status.OnlineStatus
+		if seen {
+			if wasOnline && !isOnline {
+				f := p.failures[res.serverID]
+				p.offlineSince[res.serverID] = f.since
+				log.Printf("[Poller] server %d offline after %d failed polls (%s)", res.serverID, f.count, f.reason)
+				openIncident(res.serverID, f)
+			} else if !wasOnline && isOnline {
+				since := p.offlineSince[res.serverID]
+				delete(p.offlineSince, res.serverID)
+				resolveIncident(res.serverID, time.Now())
+				// О восстановлении сообщаем, только если сообщали о падении
+				if f, ok := p.failures[res.serverID]; ok && f.alerted {
+					p.notify(func() { p.sendOnlineAlert(res.serverID, since) })
 				}
 			}
-			p.prevOnline[res.serverID] = isOnline
+		} else if isOnline {
+			// Сервер восстановился, пока поллер его не наблюдал (перезапуск, смена лидера)
+			resolveIncident(res.serverID, time.Now())
+		}
+		p.prevOnline[res.serverID] = isOnline
+		if isOnline {
+			delete(p.failures, res.serverID)
+		} else if p.offlineAlertDue(res.serverID) {
+			p.notify(func() { p.sendOfflineAlert(res.serverID) })
+		}
 
-			// Отслеживать сессии игроков
-			p.trackSessions(res.serverID, res.players, res.status.OnlineStatus)
+		// Отслеживать сессии игроков
+		p.trackSessions(res.serverID, res.players, res.status.OnlineStatus)
 
-			// Уведомить WebSocket клиентов
-			if p.OnUpdate != nil {
-				p.OnUpdate(res.serverID, res.status)
-			}
+		if res.status.Favicon != "" {
+			p.saveFavicon(res.serverID, res.status.Favicon)
+		}
 
-		case <-p.done:
-			return
+		if res.rules != nil {
+			p.saveRules(res.serverID, res.rules)
+		}
+
+		p.lastPlayers[res.serverID] = res.status.PlayersNow
+		p.sched.complete(res.serverID, res.status.PlayersNow, 0)
+
+		// Уведомить WebSocket клиентов
+		if p.OnUpdate != nil {
+			p.OnUpdate(res.serverID, res.status)
 		}
 	}
+	p.endAllSessions()
+}
+
+// detectVersionChange сравнивает версию из опроса с последней известной.
+// Пустая версия (офлайн или протокол её не сообщает) изменением не считается.
+// Вызывается только из processResults (однопоточно) — мьютекс не нужен.
+func (p *Poller) detectVersionChange(serverID uint, status *models.ServerStatus) bool {
+	if status.Version == "" {
+		return false
+	}
+	prev, seen := p.lastVersion[serverID]
+	if !seen {
+		// Первый опрос после запуска — берём версию из БД
+		var stored models.ServerStatus
+		database.DB.Select("version").Where("server_id = ?", serverID).First(&stored)
+		prev = stored.Version
+	}
+	p.lastVersion[serverID] = status.Version
+	if prev == "" || prev == status.Version {
+		return false
+	}
+	now := time.Now()
+	status.VersionChangedAt = &now
+	log.Printf("[Poller] server %d version changed: %s → %s", serverID, prev, status.Version)
+	return true
+}
+
+// saveFavicon сохраняет иконку сервера, если она изменилась с прошлого опроса.
+// Вызывается только из processResults (однопоточно) — мьютекс не нужен.
+func (p *Poller) saveFavicon(serverID uint, data string) {
+	sum := sha256.Sum256([]byte(data))
+	hash := hex.EncodeToString(sum[:])
+
+	prev, seen := p.faviconHash[serverID]
+	if !seen {
+		var stored models.ServerFavicon
+		database.DB.Select("hash").Where("server_id = ?", serverID).First(&stored)
+		prev = stored.Hash
+	}
+	p.faviconHash[serverID] = hash
+	if prev == hash {
+		return
+	}
+	database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "server_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"data", "hash", "updated_at"}),
+	}).Create(&models.ServerFavicon{ServerID: serverID, Data: data, Hash: hash})
 }
 
 // trackSessions обновляет in-memory состояние и сохраняет события join/leave в БД.
 // Вызывается только из processResults (однопоточно) — мьютекс не нужен.
-func (p *Poller) trackSessions(serverID uint, newPlayers []string, online bool) {
+func (p *Poller) trackSessions(serverID uint, newPlayers []models.ServerPlayer, online bool) {
 	now := time.Now()
 	prev := p.playerState[serverID]
 	if prev == nil {
@@ -292,27 +540,21 @@ func (p *Poller) trackSessions(serverID uint, newPlayers []string, online bool)
 
 	if !online {
 		// Сервер офлайн — закрываем все открытые сессии
-		for name, joinTime := range prev {
-			dur := int(now.Sub(joinTime).Seconds())
-			database.DB.Model(&models.PlayerSession{}).
-				Where("server_id = ? AND ended_at IS NULL AND player_name = ?", serverID, name).
-				Updates(map[string]interface{}{"ended_at": now, "duration": dur})
-		}
-		delete(p.playerState, serverID)
+		p.endSessions(serverID, now)
 		return
 	}
 
-	// Строим set текущих игроков
-	newSet := make(map[string]bool, len(newPlayers))
-	for _, name := range newPlayers {
-		if name != "" {
-			newSet[name] = true
+	// Строим set текущих игроков: имя → время на сервере по данным сервера
+	newSet := make(map[string]int, len(newPlayers))
+	for _, pl := range newPlayers {
+		if pl.Name != "" {
+			newSet[pl.Name] = pl.Duration
 		}
 	}
 
 	// Ушедшие игроки: были в prev, нет в newSet
 	for name, joinTime := range prev {
-		if !newSet[name] {
+		if _, ok := newSet[name]; !ok {
 			dur := int(now.Sub(joinTime).Seconds())
 			database.DB.Model(&models.PlayerSession{}).
 				Where("server_id = ? AND ended_at IS NULL AND player_name = ?", serverID, name).
@@ -321,21 +563,73 @@ func (p *Poller) trackSessions(serverID uint, newPlayers []string, online bool)
 		}
 	}
 
-	// Новые игроки: есть в newSet, нет в prev
-	for name := range newSet {
+	// Новые игроки: есть в newSet, нет в prev.
+	// Если сервер сообщает время подключения (A2S), сессия начинается с реального момента входа —
+	// в том числе для игроков, зашедших до перезапуска бэкенда.
+	for name, connected := range newSet {
 		if _, exists := prev[name]; !exists {
+			joinTime := now.Add(-time.Duration(connected) * time.Second)
+			if connected > 0 {
+				if started, ok := resumeSession(serverID, name, joinTime); ok {
+					prev[name] = started
+					continue
+				}
+			}
 			database.DB.Create(&models.PlayerSession{
 				ServerID:   serverID,
 				PlayerName: name,
-				StartedAt:  now,
+				StartedAt:  joinTime,
 			})
-			prev[name] = now
+			prev[name] = joinTime
 		}
 	}
 
 	p.playerState[serverID] = prev
 }
 
+// resumeSession продолжает последнюю сессию игрока, если она закончилась позже joinTime:
+// её закрыли перезапуск или смена лидера, а игрок всё это время оставался на сервере.
+// Без этого новая сессия с начала подключения перекрыла бы старую и время игры задвоилось.
+// Возвращает начало продолженной сессии.
+func resumeSession(serverID uint, name string, joinTime time.Time) (time.Time, bool) {
+	var last models.PlayerSession
+	err := database.DB.
+		Where("server_id = ? AND player_name = ? AND ended_at >= ?", serverID, name, joinTime).
+		Order("started_at DESC").
+		First(&last).Error
+	if err != nil {
+		return time.Time{}, false
+	}
+	database.DB.Model(&last).Updates(map[string]interface{}{"ended_at": nil, "duration": 0})
+	return last.StartedAt, true
+}
+
+// endSessions закрывает все открытые сессии сервера моментом at.
+// Вызывается только из processResults (однопоточно) — мьютекс не нужен.
+func (p *Poller) endSessions(serverID uint, at time.Time) {
+	for name, joinTime := range p.playerState[serverID] {
+		dur := int(at.Sub(joinTime).Seconds())
+		database.DB.Model(&models.PlayerSession{}).
+			Where("server_id = ? AND ended_at IS NULL AND player_name = ?", serverID, name).
+			Updates(map[string]interface{}{"ended_at": at, "duration": dur})
+	}
+	delete(p.playerState, serverID)
+}
+
+// endAllSessions закрывает сессии всех серверов при остановке поллера, чтобы
+// closeOrphanSessions при следующем запуске не подставлял время запуска вместо времени выхода
+func (p *Poller) endAllSessions() {
+	now := time.Now()
+	n := 0
+	for serverID, players := range p.playerState {
+		n += len(players)
+		p.endSessions(serverID, now)
+	}
+	if n > 0 {
+		log.Printf("[Poller] closed %d player sessions on shutdown", n)
+	}
+}
+
 // closeOrphanSessions закрывает сессии, оставшиеся открытыми после предыдущего запуска
 func (p *Poller) closeOrphanSessions() {
 	result := database.DB.Model(&models.PlayerSession{}).
@@ -364,6 +658,25 @@ func (p *Poller) batchHistoryWriter() {
 	}
 }
 
+// historyRollupWorker сворачивает историю в агрегаты и удаляет устаревшую (см. пакет history)
+func (p *Poller) historyRollupWorker() {
+	ticker := time.NewTicker(historyRollupTick)
+	defer ticker.Stop()
+
+	for {
+		if err := history.Rollup(p.done); err != nil {
+			log.Printf("[Poller] history rollup error: %v", err)
+		}
+		history.ApplyRetention(p.done)
+
+		select {
+		case <-ticker.C:
+		case <-p.done:
+			return
+		}
+	}
+}
+
 func (p *Poller) flushHistoryBuffer() {
 	p.historyMu.Lock()
 	if len(p.historyBuf) == 0 {
@@ -398,7 +711,8 @@ func (p *Poller) sendOfflineAlert(serverID uint) {
 	p.sendAlert(&cfg, "🔴 Сервер офлайн — "+srv.Title, text)
 }
 
-// sendOnlineAlert отправляет уведомление о восстановлении сервера
+// sendOnlineAlert отправляет уведомление о восстановлении сервера.
+// Вызывается, только если об офлайне было отправлено уведомление (см. offlineAlertDue).
 func (p *Poller) sendOnlineAlert(serverID uint, offlineSince time.Time) {
 	var cfg models.AlertsConfig
 	if err := database.DB.Where("server_id = ? AND enabled = ? AND notify_online = ?", serverID, true, true).First(&cfg).Error; err != nil {
@@ -452,7 +766,7 @@ func (p *Poller) sendAlert(cfg *models.AlertsConfig, emailSubject, tgText string
 	}
 }
 
-// stripHTML удаляет HTML-теги из строки для email-уведомлений
+// stripHTML удаляет HTML-теги из строки и раскрывает сущности (&lt; → <) для email-уведомлений
 func stripHTML(s string) string {
 	var result strings.Builder
 	inTag := false
@@ -466,7 +780,7 @@ func stripHTML(s string) string {
 			result.WriteRune(r)
 		}
 	}
-	return result.String()
+	return html.UnescapeString(result.String())
 }
 
 // discordWorker периодически обновляет Discord-виджеты для всех серверов с включённой интеграцией
@@ -504,7 +818,7 @@ func (p *Poller) runDiscordUpdates() {
 
 func (p *Poller) sendDiscordUpdate(cfg models.DiscordConfig) {
 	var srv models.Server
-	if err := database.DB.Preload("Status").First(&srv, cfg.ServerID).Error; err != nil {
+	if err := database.DB.Preload("Status").Preload("Rules").First(&srv, cfg.ServerID).Error; err != nil {
 		return
 	}
 
@@ -514,7 +828,7 @@ func (p *Poller) sendDiscordUpdate(cfg models.DiscordConfig) {
 	if database.DB.First(&s, 1).Error == nil {
 		appURL = s.AppURL
 	}
-	payload := discordBuildPayload(siteName, appURL, &srv, srv.Status)
+	payload := discordBuildPayload(siteName, appURL, &srv, srv.Status, maintenance.Active(srv.ID, time.Now()))
 
 	msgID, err := discordSendOrUpdate(cfg.WebhookURL, cfg.MessageID, payload)
 	if err != nil {
@@ -534,6 +848,12 @@ func (p *Poller) discordSiteName() string {
 	return s.SiteName
 }
 
+// Лимиты embed: Discord отклоняет сообщение целиком, если правил слишком много
+const (
+	discordMaxRuleFields = 10 // как в embed бота
+	discordMaxEmbedChars = 6000
+)
+
 var discordGameThumbnail = map[string]string{
 	"gmod":     "https://cdn.cloudflare.steamstatic.com/steam/apps/4000/capsule_sm_120.jpg",
 	"valheim":  "https://cdn.cloudflare.steamstatic.com/steam/apps/892970/capsule_sm_120.jpg",
@@ -548,12 +868,15 @@ var discordGameThumbnail = map[string]string{
 	"arma3":    "https://cdn.cloudflare.steamstatic.com/steam/apps/107410/capsule_sm_120.jpg",
 }
 
-func discordBuildPayload(siteName, appURL string, srv *models.Server, status *models.ServerStatus) []byte {
+func discordBuildPayload(siteName, appURL string, srv *models.Server, status *models.ServerStatus, inMaintenance bool) []byte {
 	color := 10038562
 	statusVal := "🔴 Офлайн"
 	if status != nil && status.OnlineStatus {
 		color = 3066993
 		statusVal = "🟢 Онлайн"
+	} else if inMaintenance {
+		color = 15105570
+		statusVal = "🛠 Обслуживание"
 	}
 
 	title := srv.Title
@@ -608,6 +931,23 @@ func discordBuildPayload(siteName, appURL string, srv *models.Server, status *mo
 		if status.CurrentMap != "" {
 			fields = append(fields, field{Name: "Карта", Value: status.CurrentMap, Inline: true})
 		}
+		size := utf8.RuneCountInString(title) + utf8.RuneCountInString(desc) + utf8.RuneCountInString(siteName)
+		for _, f := range fields {
+			size += utf8.RuneCountInString(f.Name) + utf8.RuneCountInString(f.Value)
+		}
+		added := 0
+		for _, r := range srv.Rules {
+			if r.Value == "" {
+				continue
+			}
+			n := utf8.RuneCountInString(r.Key) + utf8.RuneCountInString(r.Value)
+			if added == discordMaxRuleFields || size+n > discordMaxEmbedChars {
+				break
+			}
+			fields = append(fields, field{Name: r.Key, Value: r.Value, Inline: true})
+			size += n
+			added++
+		}
 	}
 
 	e := embed{
@@ -658,40 +998,3 @@ func discordSendOrUpdate(webhookURL, messageID string, payload []byte) (string,
 	_ = json.NewDecoder(resp.Body).Decode(&msgResp)
 	return msgResp.ID, nil
 }
-
-// scheduler — Smart Poller: адаптирует интервал опроса в зависимости от активности
-func (p *Poller) scheduler() {
-	ticker := time.NewTicker(schedulerTick)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			var servers []models.Server
-			database.DB.Preload("Status").Find(&servers)
-
-			now := time.Now()
-			for _, srv := range servers {
-				s := srv
-
-				// Выбираем интервал: пустой сервер → 1 мин, активная игра → 10 сек
-				interval := emptyPollInterval
-				if s.Status != nil && s.Status.PlayersNow > 0 {
-					interval = activePollInterval
-				}
-
-				// Нужен ли новый опрос?
-				if s.Status == nil || now.Sub(s.Status.LastUpdate) >= interval {
-					select {
-					case p.jobs <- pollJob{server: s}:
-					default:
-						// Канал переполнен — пропускаем этот цикл
-					}
-				}
-			}
-
-		case <-p.done:
-			return
-		}
-	}
-}
@@ -0,0 +1,41 @@
+package poller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+func TestDropBots(t *testing.T) {
+	players := []models.ServerPlayer{
+		{Name: "alice", Duration: 300},
+		{Name: "bot-1", Duration: 3600},
+		{Name: "bob", Duration: 60},
+		{Name: "bot-2", Duration: 3500},
+	}
+	names := func(ps []models.ServerPlayer) []string {
+		var out []string
+		for _, p := range ps {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name         string
+		humans, bots int
+		want         []string
+	}{
+		{"no bots", 4, 0, []string{"alice", "bot-1", "bob", "bot-2"}},
+		{"bots dropped by duration", 2, 2, []string{"bob", "alice"}},
+		// Сервер не прислал часть ботов (пустые имена) — людей не трогаем
+		{"fewer entries than bots", 3, 2, []string{"bob", "alice", "bot-2"}},
+		{"list already without bots", 4, 3, []string{"alice", "bot-1", "bob", "bot-2"}},
+	}
+	for _, tt := range tests {
+		if got := names(dropBots(players, tt.humans, tt.bots)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: dropBots() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
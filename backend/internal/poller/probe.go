@@ -0,0 +1,93 @@
+package poller
+
+import (
+	"log"
+	"sort"
+	"time"
+)
+
+// ProbeResult — ответ одного протокола при автоопределении игры
+type ProbeResult struct {
+	Protocol   string   `json:"protocol"`
+	Games      []string `json:"games"` // game_type, которые опрашиваются этим протоколом
+	Port       uint16   `json:"port"`
+	ServerName string   `json:"server_name"`
+	Map        string   `json:"map"`
+	PlayersNow int      `json:"players_now"`
+	PlayersMax int      `json:"players_max"`
+	PingMS     int      `json:"ping_ms"`
+	Version    string   `json:"version,omitempty"`
+}
+
+// probeTimeout — верхняя граница ожидания: Minecraft может последовательно
+// попробовать JSON и legacy ping, каждый со своим таймаутом
+const probeTimeout = 3 * udpTimeout
+
+// Probe опрашивает адрес всеми зарегистрированными протоколами параллельно и возвращает
+// ответившие за probeTimeout, отсортированные по пингу. port = 0 — порт протокола по умолчанию.
+func Probe(ip string, port uint16) []ProbeResult {
+	registry.RLock()
+	protocols := make([]Protocol, 0, len(registry.protocols))
+	for _, p := range registry.protocols {
+		protocols = append(protocols, p)
+	}
+	gamesByProto := make(map[string][]string)
+	for _, g := range registry.games {
+		gamesByProto[g.Protocol] = append(gamesByProto[g.Protocol], g.Type)
+	}
+	registry.RUnlock()
+
+	// Буфер на все протоколы: опоздавшие горутины не блокируются после выхода
+	found := make(chan *ProbeResult, len(protocols))
+	for _, proto := range protocols {
+		go func(proto Protocol) {
+			// Паника в разборе ответа одного протокола не должна ронять весь процесс
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("[Probe] %s panic: %v", proto.Name(), r)
+					found <- nil
+				}
+			}()
+			p := port
+			if p == 0 {
+				p = proto.DefaultPort()
+			}
+			status, err := proto.Info(ip, p)
+			if err != nil || status == nil || !status.OnlineStatus {
+				found <- nil
+				return
+			}
+			games := append([]string(nil), gamesByProto[proto.Name()]...)
+			sort.Strings(games)
+			found <- &ProbeResult{
+				Protocol:   proto.Name(),
+				Games:      games,
+				Port:       p,
+				ServerName: status.ServerName,
+				Map:        status.CurrentMap,
+				PlayersNow: status.PlayersNow,
+				PlayersMax: status.PlayersMax,
+				PingMS:     status.PingMS,
+				Version:    status.Version,
+			}
+		}(proto)
+	}
+
+	results := []ProbeResult{}
+	deadline := time.NewTimer(probeTimeout)
+	defer deadline.Stop()
+collect:
+	for pending := len(protocols); pending > 0; pending-- {
+		select {
+		case r := <-found:
+			if r != nil {
+				results = append(results, *r)
+			}
+		case <-deadline.C:
+			break collect
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].PingMS < results[j].PingMS })
+	return results
+}
@@ -0,0 +1,119 @@
+package poller
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// ErrNotSupported возвращается протоколом, который не умеет запрашиваемую операцию
+var ErrNotSupported = errors.New("operation not supported by protocol")
+
+// Capabilities — какие запросы поддерживает протокол помимо базовой информации
+type Capabilities struct {
+	Players bool `json:"players"`
+	Rules   bool `json:"rules"`
+}
+
+// Protocol — реализация протокола опроса игрового сервера.
+// Каждый файл протокола (a2s.go, minecraft.go, samp.go, …) регистрирует себя в init().
+type Protocol interface {
+	// Name — идентификатор протокола ("a2s", "minecraft", "samp")
+	Name() string
+	// DefaultPort — порт опроса по умолчанию, если игра не задаёт свой
+	DefaultPort() uint16
+	Capabilities() Capabilities
+	Info(ip string, port uint16) (*models.ServerStatus, error)
+	Players(ip string, port uint16) ([]models.ServerPlayer, error)
+	Rules(ip string, port uint16) (map[string]string, error)
+}
+
+// Game — поддерживаемый game_type и протокол, которым он опрашивается
+type Game struct {
+	Type        string       `json:"type"`
+	Name        string       `json:"name"`
+	Protocol    string       `json:"protocol"`
+	DefaultPort uint16       `json:"default_port"`
+	Caps        Capabilities `json:"capabilities"`
+}
+
+// fallbackProtocol используется для неизвестных game_type
+const fallbackProtocol = "a2s"
+
+var registry = struct {
+	sync.RWMutex
+	protocols map[string]Protocol
+	games     map[string]Game
+}{
+	protocols: make(map[string]Protocol),
+	games:     make(map[string]Game),
+}
+
+// RegisterProtocol добавляет протокол в реестр
+func RegisterProtocol(p Protocol) {
+	registry.Lock()
+	defer registry.Unlock()
+	if _, dup := registry.protocols[p.Name()]; dup {
+		panic("poller: protocol registered twice: " + p.Name())
+	}
+	registry.protocols[p.Name()] = p
+}
+
+// RegisterGame привязывает game_type к протоколу. port = 0 — порт протокола по умолчанию.
+func RegisterGame(gameType, name, protocol string, port uint16) {
+	registry.Lock()
+	defer registry.Unlock()
+	p, ok := registry.protocols[protocol]
+	if !ok {
+		panic("poller: unknown protocol " + protocol + " for game " + gameType)
+	}
+	if port == 0 {
+		port = p.DefaultPort()
+	}
+	registry.games[gameType] = Game{
+		Type:        gameType,
+		Name:        name,
+		Protocol:    protocol,
+		DefaultPort: port,
+		Caps:        p.Capabilities(),
+	}
+}
+
+// ProtocolFor возвращает протокол для game_type (A2S для неизвестных типов)
+func ProtocolFor(gameType string) Protocol {
+	registry.RLock()
+	defer registry.RUnlock()
+	if g, ok := registry.games[gameType]; ok {
+		return registry.protocols[g.Protocol]
+	}
+	return registry.protocols[fallbackProtocol]
+}
+
+// LookupGame возвращает описание game_type
+func LookupGame(gameType string) (Game, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	g, ok := registry.games[gameType]
+	return g, ok
+}
+
+// Games возвращает все зарегистрированные game_type, отсортированные по типу
+func Games() []Game {
+	registry.RLock()
+	defer registry.RUnlock()
+	out := make([]Game, 0, len(registry.games))
+	for _, g := range registry.games {
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Type < out[j].Type })
+	return out
+}
+
+// hostPort собирает адрес для net.Dial (корректно для IPv6)
+func hostPort(ip string, port uint16) string {
+	return net.JoinHostPort(ip, strconv.Itoa(int(port)))
+}
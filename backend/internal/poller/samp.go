@@ -2,67 +2,111 @@ package poller
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"net"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/RJ-Bond/js-monitoring/internal/models"
 )
 
-// buildSAMPPacket формирует заголовок пакета SA-MP
-func buildSAMPPacket(ip string, port uint16, packetType byte) ([]byte, error) {
-	parts := strings.Split(ip, ".")
-	if len(parts) != 4 {
-		return nil, fmt.Errorf("invalid IPv4 address: %s", ip)
+// sampProtocol — SA-MP / open.mp query
+type sampProtocol struct{}
+
+func (sampProtocol) Name() string               { return "samp" }
+func (sampProtocol) DefaultPort() uint16        { return 7777 }
+func (sampProtocol) Capabilities() Capabilities { return Capabilities{Players: true, Rules: true} }
+
+func (sampProtocol) Info(ip string, port uint16) (*models.ServerStatus, error) {
+	return QuerySAMP(ip, port)
+}
+
+func (sampProtocol) Players(ip string, port uint16) ([]models.ServerPlayer, error) {
+	return QuerySAMPPlayers(ip, port)
+}
+
+func (sampProtocol) Rules(ip string, port uint16) (map[string]string, error) {
+	return QuerySAMPRules(ip, port)
+}
+
+func init() {
+	RegisterProtocol(sampProtocol{})
+	RegisterGame("samp", "SA:MP", "samp", 0)
+}
+
+// sampResolve возвращает IPv4-адрес сервера: в заголовке пакета SA-MP адрес передаётся
+// четырьмя байтами, поэтому доменное имя нужно разрешить до построения пакета.
+func sampResolve(host string) (net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		if v4 := ip.To4(); v4 != nil {
+			return v4, nil
+		}
+		return nil, fmt.Errorf("SA-MP query requires IPv4 address: %s", host)
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), udpTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIP(ctx, "ip4", host)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("resolve %s: no IPv4 address", host)
+	}
+	return addrs[0].To4(), nil
+}
+
+// buildSAMPPacket формирует заголовок пакета SA-MP
+func buildSAMPPacket(ip net.IP, port uint16, packetType byte) []byte {
 	var buf bytes.Buffer
 	buf.WriteString("SAMP")
-	for _, p := range parts {
-		n, _ := strconv.Atoi(p)
-		buf.WriteByte(byte(n))
-	}
+	buf.Write(ip.To4())
 	buf.WriteByte(byte(port & 0xFF))
 	buf.WriteByte(byte(port >> 8))
 	buf.WriteByte(packetType)
-	return buf.Bytes(), nil
+	return buf.Bytes()
 }
 
-// QuerySAMP опрашивает SA-MP/open.mp сервер по UDP (пакет 'i' — информация)
-func QuerySAMP(ip string, port uint16) (*models.ServerStatus, error) {
-	pkt, err := buildSAMPPacket(ip, port, 'i')
+// sampQuery отправляет пакет указанного типа и возвращает тело ответа (после 11-байтного заголовка)
+func sampQuery(host string, port uint16, packetType byte, bufSize int) (*bytes.Reader, time.Duration, error) {
+	ip, err := sampResolve(host)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	addr := fmt.Sprintf("%s:%d", ip, port)
-	conn, err := net.DialTimeout("udp", addr, 3*time.Second)
+	conn, err := net.DialTimeout("udp", hostPort(ip.String(), port), udpTimeout)
 	if err != nil {
-		return nil, fmt.Errorf("udp dial: %w", err)
+		return nil, 0, fmt.Errorf("udp dial: %w", err)
 	}
 	defer conn.Close()
-	conn.SetDeadline(time.Now().Add(3 * time.Second)) //nolint:errcheck
+	conn.SetDeadline(time.Now().Add(udpTimeout)) //nolint:errcheck
 
 	start := time.Now()
-	if _, err := conn.Write(pkt); err != nil {
-		return nil, fmt.Errorf("write: %w", err)
+	if _, err := conn.Write(buildSAMPPacket(ip, port, packetType)); err != nil {
+		return nil, 0, fmt.Errorf("write: %w", err)
 	}
 
-	resp := make([]byte, 512)
+	resp := make([]byte, bufSize)
 	n, err := conn.Read(resp)
 	if err != nil {
-		return nil, fmt.Errorf("read: %w", err)
+		return nil, 0, fmt.Errorf("read: %w", err)
 	}
-	pingMS := int(time.Since(start).Milliseconds())
+	rtt := time.Since(start)
 
 	// Проверяем заголовок ответа: "SAMP" + IP(4) + Port(2) + PacketType(1) = 11 bytes
-	if n < 12 || string(resp[:4]) != "SAMP" {
-		return nil, fmt.Errorf("invalid SAMP response (len=%d)", n)
+	if n < 12 || string(resp[:4]) != "SAMP" || resp[10] != packetType {
+		return nil, 0, fmt.Errorf("invalid SAMP response (len=%d)", n)
 	}
+	return bytes.NewReader(resp[11:n]), rtt, nil
+}
 
-	r := bytes.NewReader(resp[11:])
+// QuerySAMP опрашивает SA-MP/open.mp сервер по UDP (пакет 'i' — информация)
+func QuerySAMP(ip string, port uint16) (*models.ServerStatus, error) {
+	r, rtt, err := sampQuery(ip, port, 'i', 512)
+	if err != nil {
+		return nil, err
+	}
 
 	var password uint8
 	binary.Read(r, binary.LittleEndian, &password) //nolint:errcheck
@@ -95,41 +139,57 @@ func QuerySAMP(ip string, port uint16) (*models.ServerStatus, error) {
 		PlayersMax:   int(maxPlayers),
 		CurrentMap:   string(gamemode),
 		ServerName:   string(hostname),
-		PingMS:       pingMS,
+		PingMS:       int(rtt.Milliseconds()),
+		Password:     password == 1,
 	}, nil
 }
 
-// QuerySAMPPlayers опрашивает SA-MP сервер по UDP (пакет 'd' — детальный список игроков)
-func QuerySAMPPlayers(ip string, port uint16) ([]models.ServerPlayer, error) {
-	pkt, err := buildSAMPPacket(ip, port, 'd')
+// QuerySAMPRules опрашивает SA-MP сервер по UDP (пакет 'r' — правила: version, weburl, worldtime, weather …)
+func QuerySAMPRules(ip string, port uint16) (map[string]string, error) {
+	r, _, err := sampQuery(ip, port, 'r', 2048)
 	if err != nil {
 		return nil, err
 	}
 
-	addr := fmt.Sprintf("%s:%d", ip, port)
-	conn, err := net.DialTimeout("udp", addr, 3*time.Second)
-	if err != nil {
-		return nil, fmt.Errorf("udp dial: %w", err)
+	var ruleCount uint16
+	if err := binary.Read(r, binary.LittleEndian, &ruleCount); err != nil {
+		return nil, fmt.Errorf("read rule count: %w", err)
 	}
-	defer conn.Close()
-	conn.SetDeadline(time.Now().Add(3 * time.Second)) //nolint:errcheck
 
-	if _, err := conn.Write(pkt); err != nil {
-		return nil, fmt.Errorf("write: %w", err)
+	// Имя и значение — строки с длиной в 1 байт
+	readString := func() (string, bool) {
+		n, err := r.ReadByte()
+		if err != nil || int(n) > r.Len() {
+			return "", false
+		}
+		buf := make([]byte, n)
+		r.Read(buf) //nolint:errcheck
+		return string(buf), true
 	}
 
-	resp := make([]byte, 4096)
-	n, err := conn.Read(resp)
-	if err != nil {
-		return nil, fmt.Errorf("read: %w", err)
+	rules := make(map[string]string, ruleCount)
+	for i := uint16(0); i < ruleCount; i++ {
+		name, ok := readString()
+		if !ok {
+			break
+		}
+		value, ok := readString()
+		if !ok {
+			break
+		}
+		rules[name] = value
 	}
+	return rules, nil
+}
 
-	if n < 12 || string(resp[:4]) != "SAMP" {
-		return nil, fmt.Errorf("invalid SAMP response (len=%d)", n)
+// QuerySAMPPlayers опрашивает SA-MP сервер по UDP (пакет 'd' — детальный список игроков).
+// Сервер не отвечает на 'd', если игроков больше 100.
+func QuerySAMPPlayers(ip string, port uint16) ([]models.ServerPlayer, error) {
+	r, _, err := sampQuery(ip, port, 'd', 4096)
+	if err != nil {
+		return nil, err
 	}
 
-	r := bytes.NewReader(resp[11:])
-
 	var playerCount uint16
 	if err := binary.Read(r, binary.LittleEndian, &playerCount); err != nil {
 		return nil, fmt.Errorf("read player count: %w", err)
@@ -154,13 +214,16 @@ func QuerySAMPPlayers(ip string, port uint16) ([]models.ServerPlayer, error) {
 		if _, err := r.Read(nameBuf); err != nil {
 			break
 		}
-		// score (int32 LE) — пропускаем
-		var score int32
+		// score (int32 LE) + ping (int32 LE)
+		var score, ping int32
 		binary.Read(r, binary.LittleEndian, &score) //nolint:errcheck
+		if err := binary.Read(r, binary.LittleEndian, &ping); err != nil {
+			break
+		}
 
 		name := strings.TrimSpace(string(nameBuf))
 		if name != "" {
-			players = append(players, models.ServerPlayer{Name: name})
+			players = append(players, models.ServerPlayer{Name: name, Score: int(score), Ping: int(ping)})
 		}
 	}
 	return players, nil
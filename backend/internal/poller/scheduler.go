@@ -0,0 +1,349 @@
+package poller
+
+import (
+	"container/heap"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/database"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+const (
+	// schedulerResync — полная сверка очереди с БД на случай изменений в обход API
+	// (восстановление бэкапа, удаление аккаунтов).
+	schedulerResync = 5 * time.Minute
+	// schedulerWatch — проверка servers.updated_at: изменения через API реплики без
+	// опросчика (она не лидер и Invalidate вызвать не может) подхватываются за секунды
+	schedulerWatch = 5 * time.Second
+	// failureRecheckInterval — повторный опрос сервера с неподтверждённой неудачей
+	failureRecheckInterval = 5 * time.Second
+	// maxInFlightPerIP — одновременных опросов одного IP (хостинги держат десятки серверов на одном адресе)
+	maxInFlightPerIP = 4
+	ipRetryDelay     = 500 * time.Millisecond
+	// dropRetryDelay — через сколько повторить задание, не поместившееся в канал воркеров
+	dropRetryDelay = 1 * time.Second
+)
+
+// schedItem — сервер в очереди опроса
+type schedItem struct {
+	serverID uint
+	due      time.Time
+	index    int // позиция в куче, -1 — не в куче (опрашивается)
+}
+
+// schedHeap — min-куча по времени следующего опроса
+type schedHeap []*schedItem
+
+func (h schedHeap) Len() int           { return len(h) }
+func (h schedHeap) Less(i, j int) bool { return h[i].due.Before(h[j].due) }
+func (h schedHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *schedHeap) Push(x any) {
+	it := x.(*schedItem)
+	it.index = len(*h)
+	*h = append(*h, it)
+}
+
+func (h *schedHeap) Pop() any {
+	old := *h
+	n := len(old)
+	it := old[n-1]
+	old[n-1] = nil
+	it.index = -1
+	*h = old[:n-1]
+	return it
+}
+
+// scheduleQueue — очередь опроса в памяти. Конфигурация серверов читается из БД при
+// старте, сверке раз в schedulerResync и при Invalidate конкретного сервера; раз в
+// schedulerWatch читаются только id и updated_at.
+type scheduleQueue struct {
+	mu sync.Mutex
+
+	heap    schedHeap
+	items   map[uint]*schedItem
+	servers map[uint]models.Server // конфигурация без Status
+
+	inFlight   map[uint]string // serverID → IP опрашиваемых сейчас серверов
+	ipInFlight map[string]int
+	dirty      map[uint]bool // изменён во время опроса — опросить повторно сразу
+
+	dropped     uint64
+	rateLimited uint64
+
+	wake chan struct{}
+}
+
+func newScheduleQueue() *scheduleQueue {
+	return &scheduleQueue{
+		items:      make(map[uint]*schedItem),
+		servers:    make(map[uint]models.Server),
+		inFlight:   make(map[uint]string),
+		ipInFlight: make(map[string]int),
+		dirty:      make(map[uint]bool),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// scheduleLocked ставит сервер в очередь (или переносит) на момент due
+func (q *scheduleQueue) scheduleLocked(serverID uint, due time.Time) {
+	if _, busy := q.inFlight[serverID]; busy {
+		return // после опроса complete поставит сервер в очередь сам
+	}
+	if it, ok := q.items[serverID]; ok {
+		it.due = due
+		heap.Fix(&q.heap, it.index)
+		return
+	}
+	it := &schedItem{serverID: serverID, due: due}
+	q.items[serverID] = it
+	heap.Push(&q.heap, it)
+}
+
+func (q *scheduleQueue) removeLocked(serverID uint) {
+	if it, ok := q.items[serverID]; ok {
+		heap.Remove(&q.heap, it.index)
+		delete(q.items, serverID)
+	}
+	delete(q.servers, serverID)
+	delete(q.dirty, serverID)
+}
+
+// load сверяет очередь с таблицей серверов
+func (q *scheduleQueue) load() {
+	var servers []models.Server
+	if err := database.DB.Preload("Status").Find(&servers).Error; err != nil {
+		log.Printf("[Poller] scheduler resync failed: %v", err)
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	seen := make(map[uint]bool, len(servers))
+	now := time.Now()
+	for _, srv := range servers {
+		seen[srv.ID] = true
+		status := srv.Status
+		srv.Status = nil
+		_, known := q.servers[srv.ID]
+		q.servers[srv.ID] = srv
+		if known {
+			continue
+		}
+		due := now
+		if status != nil {
+			due = status.LastUpdate.Add(PollInterval(&srv, status.PlayersNow))
+		}
+		q.scheduleLocked(srv.ID, due)
+	}
+	for id := range q.servers {
+		if !seen[id] {
+			q.removeLocked(id)
+		}
+	}
+}
+
+// invalidate перечитывает сервер из БД и ставит его на немедленный опрос.
+// Удалённый сервер убирается из очереди.
+func (q *scheduleQueue) invalidate(serverID uint) {
+	var srv models.Server
+	err := database.DB.First(&srv, serverID).Error
+
+	q.mu.Lock()
+	if err != nil {
+		q.removeLocked(serverID)
+	} else {
+		q.servers[serverID] = srv
+		if _, busy := q.inFlight[serverID]; busy {
+			q.dirty[serverID] = true
+		}
+		q.scheduleLocked(serverID, time.Now())
+	}
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// watch сравнивает id и updated_at серверов с очередью: изменённые и новые серверы
+// перечитываются через invalidate, удалённые убираются
+func (q *scheduleQueue) watch() {
+	var rows []struct {
+		ID        uint
+		UpdatedAt time.Time
+	}
+	if err := database.DB.Model(&models.Server{}).Select("id, updated_at").Find(&rows).Error; err != nil {
+		log.Printf("[Poller] scheduler watch failed: %v", err)
+		return
+	}
+
+	var changed []uint
+	seen := make(map[uint]bool, len(rows))
+	q.mu.Lock()
+	for _, r := range rows {
+		seen[r.ID] = true
+		if srv, ok := q.servers[r.ID]; !ok || !srv.UpdatedAt.Equal(r.UpdatedAt) {
+			changed = append(changed, r.ID)
+		}
+	}
+	for id := range q.servers {
+		if !seen[id] {
+			q.removeLocked(id)
+		}
+	}
+	q.mu.Unlock()
+
+	for _, id := range changed {
+		q.invalidate(id)
+	}
+}
+
+// dispatch отправляет воркерам все задания, время которых подошло.
+// Возвращает, сколько ждать до следующего задания.
+func (q *scheduleQueue) dispatch(jobs chan<- pollJob) time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	for q.heap.Len() > 0 && !q.heap[0].due.After(now) {
+		it := heap.Pop(&q.heap).(*schedItem)
+		srv, ok := q.servers[it.serverID]
+		if !ok {
+			delete(q.items, it.serverID)
+			continue
+		}
+		if q.ipInFlight[srv.IP] >= maxInFlightPerIP {
+			q.rateLimited++
+			it.due = now.Add(ipRetryDelay)
+			heap.Push(&q.heap, it)
+			continue
+		}
+		select {
+		case jobs <- pollJob{server: srv}:
+			delete(q.items, it.serverID)
+			q.inFlight[srv.ID] = srv.IP
+			q.ipInFlight[srv.IP]++
+		default:
+			// Воркеры не успевают — повторим позже, а не теряем сервер до следующего скана
+			q.dropped++
+			it.due = now.Add(dropRetryDelay)
+			heap.Push(&q.heap, it)
+		}
+	}
+
+	if q.heap.Len() == 0 {
+		return schedulerResync
+	}
+	return time.Until(q.heap[0].due)
+}
+
+// complete снимает сервер с опроса и ставит следующий опрос через after
+// (0 — интервал по числу игроков).
+func (q *scheduleQueue) complete(serverID uint, playersNow int, after time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if ip, ok := q.inFlight[serverID]; ok {
+		delete(q.inFlight, serverID)
+		if q.ipInFlight[ip]--; q.ipInFlight[ip] <= 0 {
+			delete(q.ipInFlight, ip)
+		}
+	}
+	srv, ok := q.servers[serverID]
+	if !ok {
+		return // удалён во время опроса
+	}
+	if after == 0 {
+		after = PollInterval(&srv, playersNow)
+	}
+	if q.dirty[serverID] {
+		delete(q.dirty, serverID)
+		after = 0
+	}
+	q.scheduleLocked(serverID, time.Now().Add(after))
+}
+
+// SchedulerStats — состояние очереди опроса
+type SchedulerStats struct {
+	Servers     int    `json:"servers"`
+	QueueDepth  int    `json:"queue_depth"`  // серверов ждут своего времени
+	Overdue     int    `json:"overdue"`      // из них время опроса уже подошло
+	InFlight    int    `json:"in_flight"`    // опрашиваются сейчас
+	JobsBacklog int    `json:"jobs_backlog"` // заданий в канале воркеров
+	Dropped     uint64 `json:"dropped"`      // заданий не поместилось в канал (отложены)
+	RateLimited uint64 `json:"rate_limited"` // отложено из-за лимита на IP
+}
+
+func (q *scheduleQueue) stats() SchedulerStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	overdue := 0
+	for _, it := range q.heap {
+		if !it.due.After(now) {
+			overdue++
+		}
+	}
+	return SchedulerStats{
+		Servers:     len(q.servers),
+		QueueDepth:  q.heap.Len(),
+		Overdue:     overdue,
+		InFlight:    len(q.inFlight),
+		Dropped:     q.dropped,
+		RateLimited: q.rateLimited,
+	}
+}
+
+// PollInterval выбирает интервал опроса: пустой сервер → 1 мин, активная игра → 10 сек,
+// если у сервера не заданы свои значения
+func PollInterval(s *models.Server, playersNow int) time.Duration {
+	if playersNow > 0 {
+		if s.PollInterval > 0 {
+			return time.Duration(s.PollInterval) * time.Second
+		}
+		return activePollInterval
+	}
+	if s.EmptyPollInterval > 0 {
+		return time.Duration(s.EmptyPollInterval) * time.Second
+	}
+	return emptyPollInterval
+}
+
+// Invalidate сообщает планировщику, что сервер создан, изменён или удалён
+func (p *Poller) Invalidate(serverID uint) {
+	p.sched.invalidate(serverID)
+}
+
+// scheduler — Smart Poller: раздаёт задания воркерам по мере наступления времени опроса,
+// интервал адаптируется к активности сервера (см. PollInterval)
+func (p *Poller) scheduler() {
+	p.sched.load()
+	resync := time.NewTicker(schedulerResync)
+	defer resync.Stop()
+	watch := time.NewTicker(schedulerWatch)
+	defer watch.Stop()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		timer.Reset(p.sched.dispatch(p.jobs))
+		select {
+		case <-timer.C:
+		case <-p.sched.wake:
+		case <-resync.C:
+			p.sched.load()
+		case <-watch.C:
+			p.sched.watch()
+		case <-p.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package poller
+
+import (
+	"container/heap"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// testQueue — очередь с серверами без БД; ips[i] — адрес сервера с ID i+1
+func testQueue(ips ...string) *scheduleQueue {
+	q := newScheduleQueue()
+	for i, ip := range ips {
+		id := uint(i + 1)
+		q.servers[id] = models.Server{ID: id, IP: ip}
+	}
+	return q
+}
+
+// drain забирает из канала все отправленные задания
+func drain(jobs chan pollJob) []uint {
+	var ids []uint
+	for {
+		select {
+		case j := <-jobs:
+			ids = append(ids, j.server.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+// checkHeap проверяет свойство кучи и согласованность index/items
+func checkHeap(t *testing.T, q *scheduleQueue) {
+	t.Helper()
+	for i, it := range q.heap {
+		if it.index != i {
+			t.Fatalf("item %d: index = %d, want %d", it.serverID, it.index, i)
+		}
+		if q.items[it.serverID] != it {
+			t.Fatalf("item %d: missing from items", it.serverID)
+		}
+		if i > 0 && q.heap.Less(i, (i-1)/2) {
+			t.Fatalf("heap order broken at %d", i)
+		}
+	}
+	if len(q.items) != q.heap.Len() {
+		t.Fatalf("items = %d, heap = %d", len(q.items), q.heap.Len())
+	}
+}
+
+func TestScheduleDispatchOrder(t *testing.T) {
+	q := testQueue("10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4")
+	now := time.Now()
+	q.scheduleLocked(1, now.Add(-1*time.Second))
+	q.scheduleLocked(2, now.Add(-3*time.Second))
+	q.scheduleLocked(3, now.Add(time.Hour))
+	q.scheduleLocked(4, now.Add(-2*time.Second))
+	checkHeap(t, q)
+
+	jobs := make(chan pollJob, 10)
+	wait := q.dispatch(jobs)
+	if got, want := drain(jobs), []uint{2, 4, 1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("dispatched %v, want %v", got, want)
+	}
+	if wait < 59*time.Minute || wait > time.Hour {
+		t.Fatalf("wait = %v, want about 1h", wait)
+	}
+	if len(q.inFlight) != 3 || q.heap.Len() != 1 {
+		t.Fatalf("inFlight = %d, heap = %d", len(q.inFlight), q.heap.Len())
+	}
+	checkHeap(t, q)
+}
+
+func TestScheduleReschedule(t *testing.T) {
+	q := testQueue("10.0.0.1", "10.0.0.2", "10.0.0.3")
+	now := time.Now()
+	q.scheduleLocked(1, now.Add(time.Minute))
+	q.scheduleLocked(2, now.Add(2*time.Minute))
+	q.scheduleLocked(3, now.Add(3*time.Minute))
+
+	// Перенос уже стоящего в очереди сервера не создаёт второй элемент
+	q.scheduleLocked(3, now.Add(-time.Second))
+	checkHeap(t, q)
+	if q.heap.Len() != 3 || q.heap[0].serverID != 3 {
+		t.Fatalf("heap head = %d, len %d; want 3, len 3", q.heap[0].serverID, q.heap.Len())
+	}
+}
+
+func TestScheduleRemove(t *testing.T) {
+	q := testQueue("10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5")
+	now := time.Now()
+	for id := uint(1); id <= 5; id++ {
+		q.scheduleLocked(id, now.Add(-time.Duration(id)*time.Second))
+	}
+	// Удаление из середины и из вершины кучи
+	q.removeLocked(3)
+	q.removeLocked(5)
+	checkHeap(t, q)
+	if _, ok := q.servers[3]; ok {
+		t.Fatal("removed server still configured")
+	}
+
+	jobs := make(chan pollJob, 10)
+	q.dispatch(jobs)
+	if got, want := drain(jobs), []uint{4, 2, 1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("dispatched %v, want %v", got, want)
+	}
+
+	// Элемент кучи без конфигурации сервера молча выбрасывается
+	q.servers[6] = models.Server{ID: 6, IP: "10.0.0.6"}
+	q.scheduleLocked(6, now)
+	delete(q.servers, 6)
+	q.dispatch(jobs)
+	if got := drain(jobs); len(got) != 0 {
+		t.Fatalf("dispatched %v for a deleted server", got)
+	}
+	checkHeap(t, q)
+}
+
+func TestScheduleComplete(t *testing.T) {
+	q := testQueue("10.0.0.1", "10.0.0.1", "10.0.0.2")
+	q.servers[2] = models.Server{ID: 2, IP: "10.0.0.1", PollInterval: 30, EmptyPollInterval: 120}
+	now := time.Now()
+	for id := uint(1); id <= 3; id++ {
+		q.scheduleLocked(id, now)
+	}
+	jobs := make(chan pollJob, 10)
+	q.dispatch(jobs)
+	drain(jobs)
+	if q.ipInFlight["10.0.0.1"] != 2 {
+		t.Fatalf("ipInFlight = %d, want 2", q.ipInFlight["10.0.0.1"])
+	}
+
+	// Пока сервер опрашивается, schedule его не трогает
+	q.scheduleLocked(1, now)
+	if q.heap.Len() != 0 {
+		t.Fatal("in-flight server was queued")
+	}
+
+	q.complete(1, 5, 0)              // игроки есть — интервал по умолчанию
+	q.complete(2, 0, 0)              // пустой — свой EmptyPollInterval
+	q.complete(3, 0, 15*time.Second) // явная задержка (перепроверка неудачи)
+	checkHeap(t, q)
+	if len(q.inFlight) != 0 || len(q.ipInFlight) != 0 {
+		t.Fatalf("inFlight = %v, ipInFlight = %v", q.inFlight, q.ipInFlight)
+	}
+
+	want := map[uint]time.Duration{1: activePollInterval, 2: 120 * time.Second, 3: 15 * time.Second}
+	for id, after := range want {
+		got := q.items[id].due.Sub(now)
+		if got < after || got > after+time.Second {
+			t.Errorf("server %d due in %v, want %v", id, got, after)
+		}
+	}
+	order := make([]uint, 0, 3)
+	for q.heap.Len() > 0 {
+		order = append(order, heap.Pop(&q.heap).(*schedItem).serverID)
+	}
+	if !reflect.DeepEqual(order, []uint{1, 3, 2}) {
+		t.Errorf("queue order %v, want [1 3 2]", order)
+	}
+}
+
+func TestScheduleCompleteDirtyAndDeleted(t *testing.T) {
+	q := testQueue("10.0.0.1", "10.0.0.2")
+	now := time.Now()
+	q.scheduleLocked(1, now)
+	q.scheduleLocked(2, now)
+	jobs := make(chan pollJob, 10)
+	q.dispatch(jobs)
+	drain(jobs)
+
+	// Изменён во время опроса — опрашивается снова сразу
+	q.dirty[1] = true
+	q.complete(1, 5, 0)
+	if it := q.items[1]; it == nil || it.due.After(time.Now()) {
+		t.Fatal("dirty server not rescheduled immediately")
+	}
+	if q.dirty[1] {
+		t.Fatal("dirty flag not cleared")
+	}
+
+	// Удалён во время опроса — в очередь не возвращается
+	q.removeLocked(2)
+	q.complete(2, 0, 0)
+	if _, ok := q.items[2]; ok {
+		t.Fatal("deleted server rescheduled")
+	}
+	checkHeap(t, q)
+}
+
+func TestScheduleBackpressure(t *testing.T) {
+	q := testQueue("10.0.0.1", "10.0.0.1", "10.0.0.1", "10.0.0.1", "10.0.0.1", "10.0.0.2", "10.0.0.3")
+	now := time.Now()
+	for id := uint(1); id <= 7; id++ {
+		q.scheduleLocked(id, now.Add(-time.Duration(8-id)*time.Second))
+	}
+
+	// Канал на 5 заданий: пятый сервер 10.0.0.1 упирается в лимит на IP, седьмой — в канал
+	jobs := make(chan pollJob, maxInFlightPerIP+1)
+	q.dispatch(jobs)
+	if got, want := drain(jobs), []uint{1, 2, 3, 4, 6}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("dispatched %v, want %v", got, want)
+	}
+	if q.rateLimited != 1 || q.dropped != 1 {
+		t.Fatalf("rateLimited = %d, dropped = %d; want 1, 1", q.rateLimited, q.dropped)
+	}
+	if it := q.items[5]; it == nil || it.due.Sub(now) < ipRetryDelay {
+		t.Fatal("rate-limited server not deferred")
+	}
+	if it := q.items[7]; it == nil || it.due.Sub(now) < dropRetryDelay {
+		t.Fatal("dropped server not deferred")
+	}
+	checkHeap(t, q)
+
+	stats := q.stats()
+	if stats.InFlight != 5 || stats.QueueDepth != 2 || stats.Overdue != 0 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
@@ -0,0 +1,240 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Типы пакетов Source RCON Protocol
+// https://developer.valvesoftware.com/wiki/Source_RCON_Protocol
+const (
+	typeResponseValue = 0
+	typeExecCommand   = 2
+	typeAuthResponse  = 2
+	typeAuth          = 3
+
+	// typeMinecraftTerminator — заведомо неизвестный тип пакета. Minecraft отвечает
+	// на него "Unknown request" с тем же ID, что и служит маркером конца ответа.
+	typeMinecraftTerminator = 100
+
+	// Размер пакета = ID(4) + Type(4) + Body + 2 нулевых байта
+	packetHeaderSize = 8
+	packetMinSize    = packetHeaderSize + 2
+	// Source режет ответы по 4096 байт, Minecraft — по 4096 байт полезной нагрузки
+	packetMaxSize = 4096 + packetMinSize
+
+	// Minecraft отбрасывает команды длиннее 1446 байт
+	minecraftMaxCommand = 1446
+
+	DefaultTimeout = 5 * time.Second
+)
+
+// Variant — диалект RCON-протокола
+type Variant int
+
+const (
+	Source Variant = iota
+	Minecraft
+)
+
+func (v Variant) String() string {
+	if v == Minecraft {
+		return "minecraft"
+	}
+	return "source"
+}
+
+var (
+	ErrAuthFailed      = errors.New("rcon: authentication failed")
+	ErrUnsupportedGame = errors.New("rcon: game type does not support RCON")
+	ErrCommandTooLong  = errors.New("rcon: command too long")
+)
+
+// VariantFor возвращает диалект RCON для game_type сервера.
+// Игры без RCON (SA-MP, FiveM, Bedrock, Terraria) и BattlEye RCon (DayZ, Arma 3) не поддерживаются.
+func VariantFor(gameType string) (Variant, error) {
+	switch gameType {
+	case "minecraft":
+		return Minecraft, nil
+	case "source", "gmod", "rust", "squad", "vrising":
+		return Source, nil
+	default:
+		return 0, ErrUnsupportedGame
+	}
+}
+
+// DefaultPort возвращает порт RCON по умолчанию: Source слушает TCP на игровом порту,
+// Minecraft — на отдельном rcon.port (25575).
+func DefaultPort(v Variant, gamePort uint16) uint16 {
+	if v == Minecraft {
+		return 25575
+	}
+	return gamePort
+}
+
+// Client — TCP-соединение с RCON-сервером. Безопасен для использования из нескольких горутин:
+// команды выполняются последовательно.
+type Client struct {
+	conn    net.Conn
+	variant Variant
+	timeout time.Duration
+	nextID  int32
+	mu      sync.Mutex
+}
+
+// Dial подключается к RCON-серверу и проходит аутентификацию
+func Dial(v Variant, ip string, port uint16, password string, timeout time.Duration) (*Client, error) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	addr := net.JoinHostPort(ip, strconv.Itoa(int(port)))
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("rcon: connect failed: %w", err)
+	}
+	c := &Client{conn: conn, variant: v, timeout: timeout}
+	if err := c.auth(password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close закрывает соединение
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) id() int32 {
+	c.nextID++
+	if c.nextID <= 0 {
+		c.nextID = 1
+	}
+	return c.nextID
+}
+
+func (c *Client) auth(password string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+	reqID := c.id()
+	if err := c.writePacket(reqID, typeAuth, password); err != nil {
+		return fmt.Errorf("rcon: write auth: %w", err)
+	}
+
+	// Source перед SERVERDATA_AUTH_RESPONSE присылает пустой SERVERDATA_RESPONSE_VALUE —
+	// пропускаем всё, пока не придёт ответ на авторизацию.
+	for {
+		id, typ, _, err := c.readPacket()
+		if err != nil {
+			return fmt.Errorf("rcon: read auth response: %w", err)
+		}
+		if typ != typeAuthResponse {
+			continue
+		}
+		if id == -1 {
+			return ErrAuthFailed
+		}
+		if id != reqID {
+			return fmt.Errorf("rcon: unexpected auth response id %d", id)
+		}
+		return nil
+	}
+}
+
+// Execute отправляет команду и передаёт в onChunk тело каждого пакета ответа по мере получения.
+// Ответ может состоять из нескольких пакетов: после команды отправляется пакет-терминатор,
+// и сервер обрабатывает его только после того, как ответит на команду целиком.
+func (c *Client) Execute(command string, onChunk func(string)) error {
+	if c.variant == Minecraft && len(command) > minecraftMaxCommand {
+		return ErrCommandTooLong
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+
+	cmdID := c.id()
+	termID := c.id()
+	if err := c.writePacket(cmdID, typeExecCommand, command); err != nil {
+		return fmt.Errorf("rcon: write command: %w", err)
+	}
+
+	// Source зеркалит пустой SERVERDATA_RESPONSE_VALUE, Minecraft — отвечает на неизвестный тип.
+	termType := int32(typeResponseValue)
+	if c.variant == Minecraft {
+		termType = typeMinecraftTerminator
+	}
+	if err := c.writePacket(termID, termType, ""); err != nil {
+		return fmt.Errorf("rcon: write terminator: %w", err)
+	}
+
+	for {
+		id, _, body, err := c.readPacket()
+		if err != nil {
+			return fmt.Errorf("rcon: read response: %w", err)
+		}
+		switch id {
+		case cmdID:
+			if body != "" && onChunk != nil {
+				onChunk(body)
+			}
+			// Продлеваем дедлайн: длинные ответы (status, cvarlist) приходят пачкой пакетов
+			_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+		case termID:
+			// Source вслед за эхом терминатора шлёт ещё один пакет с телом 0x00000001
+			// и тем же ID — он будет проигнорирован следующим вызовом как чужой.
+			return nil
+		default:
+			// Хвосты предыдущих команд
+		}
+	}
+}
+
+// Exec — вариант Execute, собирающий ответ целиком
+func (c *Client) Exec(command string) (string, error) {
+	var out bytes.Buffer
+	err := c.Execute(command, func(chunk string) { out.WriteString(chunk) })
+	return out.String(), err
+}
+
+func (c *Client) writePacket(id, typ int32, body string) error {
+	size := int32(packetMinSize + len(body))
+	var buf bytes.Buffer
+	buf.Grow(int(size) + 4)
+	binary.Write(&buf, binary.LittleEndian, size) //nolint:errcheck
+	binary.Write(&buf, binary.LittleEndian, id)   //nolint:errcheck
+	binary.Write(&buf, binary.LittleEndian, typ)  //nolint:errcheck
+	buf.WriteString(body)
+	buf.Write([]byte{0, 0})
+	_, err := c.conn.Write(buf.Bytes())
+	return err
+}
+
+func (c *Client) readPacket() (id, typ int32, body string, err error) {
+	var size int32
+	if err = binary.Read(c.conn, binary.LittleEndian, &size); err != nil {
+		return
+	}
+	if size < packetMinSize || size > packetMaxSize {
+		err = fmt.Errorf("invalid packet size %d", size)
+		return
+	}
+	data := make([]byte, size)
+	if _, err = io.ReadFull(c.conn, data); err != nil {
+		return
+	}
+	id = int32(binary.LittleEndian.Uint32(data[0:4]))
+	typ = int32(binary.LittleEndian.Uint32(data[4:8]))
+	body = string(bytes.TrimRight(data[packetHeaderSize:], "\x00"))
+	return
+}
@@ -0,0 +1,209 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+type packet struct {
+	id, typ int32
+	body    string
+}
+
+// fakeServer — серверная сторона TCP-соединения с кодеком пакетов RCON.
+// TCP, а не net.Pipe: сервер пишет пакеты, которые клиент прочитает только следующей командой.
+type fakeServer struct {
+	conn net.Conn
+}
+
+// newPair соединяет клиента варианта v с фейковым сервером
+func newPair(t *testing.T, v Variant) (*Client, *fakeServer) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	client, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	_ = server.SetDeadline(time.Now().Add(2 * time.Second))
+	return &Client{conn: client, variant: v, timeout: 2 * time.Second}, &fakeServer{conn: server}
+}
+
+func (s *fakeServer) read() (packet, error) {
+	var size int32
+	if err := binary.Read(s.conn, binary.LittleEndian, &size); err != nil {
+		return packet{}, err
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(s.conn, data); err != nil {
+		return packet{}, err
+	}
+	if !bytes.HasSuffix(data, []byte{0, 0}) {
+		return packet{}, errors.New("packet is not terminated with two zero bytes")
+	}
+	return packet{
+		id:   int32(binary.LittleEndian.Uint32(data[0:4])),
+		typ:  int32(binary.LittleEndian.Uint32(data[4:8])),
+		body: string(data[8 : len(data)-2]),
+	}, nil
+}
+
+func (s *fakeServer) write(p packet) {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, int32(packetMinSize+len(p.body))) //nolint:errcheck
+	binary.Write(&buf, binary.LittleEndian, p.id)                             //nolint:errcheck
+	binary.Write(&buf, binary.LittleEndian, p.typ)                            //nolint:errcheck
+	buf.WriteString(p.body)
+	buf.Write([]byte{0, 0})
+	_, _ = s.conn.Write(buf.Bytes())
+}
+
+// serve выполняет handler в горутине и возвращает канал с его ошибкой
+func (s *fakeServer) serve(handler func() error) <-chan error {
+	errc := make(chan error, 1)
+	go func() { errc <- handler() }()
+	return errc
+}
+
+func TestAuth(t *testing.T) {
+	c, srv := newPair(t, Source)
+	errc := srv.serve(func() error {
+		p, err := srv.read()
+		if err != nil {
+			return err
+		}
+		if p.typ != typeAuth || p.body != "secret" {
+			return errors.New("unexpected auth packet: " + p.body)
+		}
+		// Source сначала присылает пустой RESPONSE_VALUE
+		srv.write(packet{id: p.id, typ: typeResponseValue})
+		srv.write(packet{id: p.id, typ: typeAuthResponse})
+		return nil
+	})
+	if err := c.auth("secret"); err != nil {
+		t.Fatalf("auth() = %v", err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuthFailed(t *testing.T) {
+	c, srv := newPair(t, Source)
+	srv.serve(func() error {
+		if _, err := srv.read(); err != nil {
+			return err
+		}
+		srv.write(packet{id: -1, typ: typeAuthResponse})
+		return nil
+	})
+	if err := c.auth("wrong"); !errors.Is(err, ErrAuthFailed) {
+		t.Fatalf("auth() = %v, want ErrAuthFailed", err)
+	}
+}
+
+func TestExecuteMultiPacketSource(t *testing.T) {
+	c, srv := newPair(t, Source)
+	long := strings.Repeat("x", 4096)
+	errc := srv.serve(func() error {
+		cmd, err := srv.read()
+		if err != nil {
+			return err
+		}
+		term, err := srv.read()
+		if err != nil {
+			return err
+		}
+		if cmd.typ != typeExecCommand || cmd.body != "cvarlist" || term.typ != typeResponseValue || term.body != "" {
+			return errors.New("unexpected command or terminator packet")
+		}
+		srv.write(packet{id: cmd.id - 10, typ: typeResponseValue, body: "stale"}) // хвост старой команды
+		srv.write(packet{id: cmd.id, typ: typeResponseValue, body: long})
+		srv.write(packet{id: cmd.id, typ: typeResponseValue, body: "part 2\n"})
+		srv.write(packet{id: cmd.id, typ: typeResponseValue, body: "part 3"})
+		srv.write(packet{id: term.id, typ: typeResponseValue})
+		// Source вслед за эхом терминатора шлёт 0x00000001 с тем же ID
+		srv.write(packet{id: term.id, typ: typeResponseValue, body: "\x00\x00\x00\x01"})
+
+		// Следующая команда должна пропустить этот пакет как чужой
+		cmd, _ = srv.read()
+		term, _ = srv.read()
+		srv.write(packet{id: cmd.id, typ: typeResponseValue, body: "ok"})
+		srv.write(packet{id: term.id, typ: typeResponseValue})
+		return nil
+	})
+
+	var chunks []string
+	if err := c.Execute("cvarlist", func(s string) { chunks = append(chunks, s) }); err != nil {
+		t.Fatalf("Execute() = %v", err)
+	}
+	if len(chunks) != 3 || chunks[0] != long || chunks[1] != "part 2\n" || chunks[2] != "part 3" {
+		t.Fatalf("chunks = %d, want the 3 packets of the response", len(chunks))
+	}
+	if out, err := c.Exec("status"); err != nil || out != "ok" {
+		t.Fatalf("Exec() = %q, %v; want \"ok\"", out, err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExecuteMinecraft(t *testing.T) {
+	c, srv := newPair(t, Minecraft)
+	errc := srv.serve(func() error {
+		cmd, err := srv.read()
+		if err != nil {
+			return err
+		}
+		term, err := srv.read()
+		if err != nil {
+			return err
+		}
+		if term.typ != typeMinecraftTerminator {
+			return errors.New("minecraft terminator must use an unknown packet type")
+		}
+		srv.write(packet{id: cmd.id, typ: typeResponseValue, body: "There are 0 of a max of 20 players online"})
+		srv.write(packet{id: term.id, typ: typeResponseValue, body: "Unknown request 64"})
+		return nil
+	})
+	out, err := c.Exec("list")
+	if err != nil || !strings.HasPrefix(out, "There are 0") {
+		t.Fatalf("Exec() = %q, %v", out, err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Execute(strings.Repeat("a", minecraftMaxCommand+1), nil); !errors.Is(err, ErrCommandTooLong) {
+		t.Fatalf("long command: err = %v, want ErrCommandTooLong", err)
+	}
+}
+
+func TestReadPacketInvalidSize(t *testing.T) {
+	for _, size := range []int32{packetMinSize - 1, packetMaxSize + 1, -5} {
+		c, srv := newPair(t, Source)
+		srv.serve(func() error {
+			return binary.Write(srv.conn, binary.LittleEndian, size)
+		})
+		if _, _, _, err := c.readPacket(); err == nil || !strings.Contains(err.Error(), "invalid packet size") {
+			t.Errorf("size %d: err = %v, want invalid packet size", size, err)
+		}
+	}
+}
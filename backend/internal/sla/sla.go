@@ -0,0 +1,184 @@
+// Package sla считает аптайм сервера, взвешенный по времени. Опросы идут с разным интервалом
+// (10 сек при игре, минута на пустом сервере), поэтому доля успешных опросов завышает вес
+// активных периодов; здесь каждый опрос весит столько, сколько времени он покрывает.
+// Окна плановых работ сервера и переданные интервалы исключаются из расчёта: они не входят
+// ни в наблюдаемое время, ни в простой.
+package sla
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/RJ-Bond/js-monitoring/internal/history"
+	"github.com/RJ-Bond/js-monitoring/internal/maintenance"
+	"github.com/RJ-Bond/js-monitoring/internal/models"
+)
+
+// defaultSampleInterval — самый длинный интервал опроса по умолчанию (пустой сервер)
+const defaultSampleInterval = 60 * time.Second
+
+// Interval — отрезок времени [From, To)
+type Interval struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Day — аптайм за календарные сутки (по местному времени)
+type Day struct {
+	Date            string   `json:"date"`
+	ObservedSeconds int64    `json:"observed_seconds"`
+	DowntimeSeconds int64    `json:"downtime_seconds"`
+	Uptime          *float64 `json:"uptime"` // nil — наблюдений не было
+}
+
+// Report — аптайм сервера за период
+type Report struct {
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"`
+	ObservedSeconds int64     `json:"observed_seconds"` // время под наблюдением без исключённых интервалов
+	DowntimeSeconds int64     `json:"downtime_seconds"`
+	ExcludedSeconds int64     `json:"excluded_seconds"` // плановые работы и исключённые интервалы
+	Uptime          *float64  `json:"uptime"`           // проценты; nil — наблюдений не было
+	Days            []Day     `json:"days"`
+}
+
+// sampleGap — дольше этого одна сырая точка не покрывает: берётся двойной самый длинный
+// интервал опроса сервера, чтобы пропущенный из-за нагрузки опрос не считался дырой
+func sampleGap(srv *models.Server) time.Duration {
+	longest := defaultSampleInterval
+	for _, sec := range []int{srv.PollInterval, srv.EmptyPollInterval} {
+		if d := time.Duration(sec) * time.Second; d > longest {
+			longest = d
+		}
+	}
+	return 2 * longest
+}
+
+// Compute считает аптайм сервера за [from, to) с разбивкой по суткам, не учитывая
+// плановые работы и интервалы exclude
+func Compute(srv *models.Server, from, to time.Time, exclude []Interval) Report {
+	all := append([]Interval(nil), exclude...)
+	for _, o := range maintenance.Between(srv.ID, from.Local(), to.Local()) {
+		all = append(all, Interval{From: o.From, To: o.To})
+	}
+	return ComputeRaw(srv, from, to, all)
+}
+
+// ComputeRaw — Compute без исключения плановых работ: простой во время окон работ
+// входит в отчёт, исключаются только интервалы exclude
+func ComputeRaw(srv *models.Server, from, to time.Time, exclude []Interval) Report {
+	res := history.Resolution(time.Since(from))
+	return report(from, to, exclude, history.Segments(srv.ID, from, to, res, sampleGap(srv)))
+}
+
+// report раскладывает наблюдения segs по суткам периода [from, to) за вычетом exclude
+func report(from, to time.Time, exclude []Interval, segs []history.Segment) Report {
+	from, to = from.Local(), to.Local()
+	r := Report{From: from, To: to}
+	excl := normalize(exclude, from, to)
+	for _, iv := range excl {
+		r.ExcludedSeconds += int64(iv.To.Sub(iv.From).Seconds())
+	}
+
+	// Сутки периода; первые и последние могут быть неполными
+	dayIndex := map[string]int{}
+	for d := dayStart(from); d.Before(to); d = d.AddDate(0, 0, 1) {
+		dayIndex[d.Format("2006-01-02")] = len(r.Days)
+		r.Days = append(r.Days, Day{Date: d.Format("2006-01-02")})
+	}
+	observed := make([]float64, len(r.Days))
+	down := make([]float64, len(r.Days))
+
+	for _, seg := range segs {
+		// Отрезок делится по границам суток
+		for cur := seg.From; cur.Before(seg.To); {
+			end := dayStart(cur).AddDate(0, 0, 1)
+			if end.After(seg.To) {
+				end = seg.To
+			}
+			i, ok := dayIndex[cur.Format("2006-01-02")]
+			if ok {
+				sec := end.Sub(cur).Seconds() - overlap(excl, cur, end)
+				observed[i] += sec
+				down[i] += sec * (1 - seg.Up)
+			}
+			cur = end
+		}
+	}
+
+	var totalObserved, totalDown float64
+	for i := range r.Days {
+		r.Days[i].ObservedSeconds = int64(math.Round(observed[i]))
+		r.Days[i].DowntimeSeconds = int64(math.Round(down[i]))
+		r.Days[i].Uptime = percent(observed[i], down[i])
+		totalObserved += observed[i]
+		totalDown += down[i]
+	}
+	r.ObservedSeconds = int64(math.Round(totalObserved))
+	r.DowntimeSeconds = int64(math.Round(totalDown))
+	r.Uptime = percent(totalObserved, totalDown)
+	return r
+}
+
+func percent(observed, down float64) *float64 {
+	if observed < 1 {
+		return nil
+	}
+	p := (observed - down) / observed * 100
+	return &p
+}
+
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// normalize обрезает интервалы по периоду, сортирует и склеивает пересекающиеся
+func normalize(ivs []Interval, from, to time.Time) []Interval {
+	var out []Interval
+	for _, iv := range ivs {
+		if iv.From.Before(from) {
+			iv.From = from
+		}
+		if iv.To.After(to) {
+			iv.To = to
+		}
+		if iv.From.Before(iv.To) {
+			out = append(out, iv)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].From.Before(out[j].From) })
+
+	merged := out[:0]
+	for _, iv := range out {
+		if n := len(merged); n > 0 && !iv.From.After(merged[n-1].To) {
+			if iv.To.After(merged[n-1].To) {
+				merged[n-1].To = iv.To
+			}
+			continue
+		}
+		merged = append(merged, iv)
+	}
+	return merged
+}
+
+// overlap — сколько секунд [from, to) приходится на интервалы ivs (нормализованные)
+func overlap(ivs []Interval, from, to time.Time) float64 {
+	var sec float64
+	for _, iv := range ivs {
+		if !iv.From.Before(to) {
+			break
+		}
+		s, e := iv.From, iv.To
+		if s.Before(from) {
+			s = from
+		}
+		if e.After(to) {
+			e = to
+		}
+		if s.Before(e) {
+			sec += e.Sub(s).Seconds()
+		}
+	}
+	return sec
+}
@@ -0,0 +1,121 @@
+package sla
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/RJ-Bond/js-monitoring/internal/history"
+)
+
+// useLocal подменяет местный часовой пояс: сутки отчёта считаются по нему
+func useLocal(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = prev })
+	return loc
+}
+
+func TestNormalize(t *testing.T) {
+	loc := useLocal(t, "UTC")
+	at := func(h int) time.Time { return time.Date(2026, 3, 1, h, 0, 0, 0, loc) }
+	iv := func(a, b int) Interval { return Interval{From: at(a), To: at(b)} }
+
+	got := normalize([]Interval{
+		iv(10, 12),
+		iv(2, 5),
+		iv(4, 8),   // пересекается с 2–5
+		iv(5, 6),   // вложен в склеенный 2–8
+		iv(8, 9),   // примыкает к 2–8
+		iv(22, 30), // обрезается концом периода
+		iv(-3, 1),  // обрезается началом периода
+		iv(25, 26), // целиком за периодом
+	}, at(0), at(24))
+
+	want := []Interval{iv(0, 1), iv(2, 9), iv(10, 12), iv(22, 24)}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("normalize() = %v, want %v", got, want)
+	}
+
+	if sec := overlap(want, at(0), at(24)); sec != 12*3600 {
+		t.Errorf("overlap over the whole period = %v, want %v", sec, 12*3600)
+	}
+	if sec := overlap(want, at(8), at(11)); sec != 2*3600 {
+		t.Errorf("overlap 08–11 = %v, want %v", sec, 2*3600)
+	}
+}
+
+func TestReportOverlappingExclusions(t *testing.T) {
+	loc := useLocal(t, "UTC")
+	day1 := time.Date(2026, 3, 1, 0, 0, 0, 0, loc)
+	day2 := day1.AddDate(0, 0, 1)
+	at := func(h int) time.Time { return day2.Add(time.Duration(h) * time.Hour) }
+
+	segs := []history.Segment{
+		{From: day1, To: day2, Up: 1},
+		{From: at(0), To: at(12), Up: 0},
+		{From: at(12), To: at(18), Up: 0.5},
+		// 18:00–24:00 — поллер не работал, время не наблюдалось
+	}
+	exclude := []Interval{
+		{From: at(2), To: at(5)},
+		{From: at(4), To: at(8)},   // пересекается с предыдущим: исключено 6 часов, а не 7
+		{From: at(23), To: at(26)}, // обрезается концом периода
+	}
+
+	r := report(day1, at(24), exclude, segs)
+	if r.ExcludedSeconds != 7*3600 {
+		t.Errorf("ExcludedSeconds = %d, want %d", r.ExcludedSeconds, 7*3600)
+	}
+	if r.ObservedSeconds != 36*3600 || r.DowntimeSeconds != 9*3600 {
+		t.Errorf("observed %d, downtime %d; want %d, %d", r.ObservedSeconds, r.DowntimeSeconds, 36*3600, 9*3600)
+	}
+	if r.Uptime == nil || *r.Uptime != 75 {
+		t.Errorf("Uptime = %v, want 75", r.Uptime)
+	}
+
+	if len(r.Days) != 2 {
+		t.Fatalf("%d days, want 2", len(r.Days))
+	}
+	if d := r.Days[0]; d.Date != "2026-03-01" || d.ObservedSeconds != 86400 || d.Uptime == nil || *d.Uptime != 100 {
+		t.Errorf("day 1 = %+v", d)
+	}
+	if d := r.Days[1]; d.Date != "2026-03-02" || d.ObservedSeconds != 12*3600 || d.DowntimeSeconds != 9*3600 {
+		t.Errorf("day 2 = %+v", d)
+	}
+}
+
+func TestReportDSTDay(t *testing.T) {
+	// 29 марта 2026 в Берлине длится 23 часа
+	loc := useLocal(t, "Europe/Berlin")
+	from := time.Date(2026, 3, 29, 0, 0, 0, 0, loc)
+	to := time.Date(2026, 3, 30, 0, 0, 0, 0, loc)
+
+	r := report(from, to, nil, []history.Segment{{From: from, To: to, Up: 1}})
+	if len(r.Days) != 1 || r.Days[0].ObservedSeconds != 23*3600 {
+		t.Fatalf("days = %+v, want one day of 23h", r.Days)
+	}
+}
+
+func TestReportWithoutObservations(t *testing.T) {
+	loc := useLocal(t, "UTC")
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, loc)
+	to := from.Add(48 * time.Hour)
+
+	// Весь период исключён — наблюдать нечего, аптайм не определён
+	r := report(from, to, []Interval{{From: from, To: to}}, []history.Segment{{From: from, To: to, Up: 1}})
+	if r.Uptime != nil || r.ObservedSeconds != 0 {
+		t.Fatalf("report = %+v, want no observations", r)
+	}
+	for _, d := range r.Days {
+		if d.Uptime != nil {
+			t.Errorf("day %s uptime = %v, want nil", d.Date, *d.Uptime)
+		}
+	}
+}
@@ -27,6 +27,7 @@ import { renderMarkdown } from "@/lib/markdown";
 import SiteBrand from "@/components/SiteBrand";
 import AlertConfigModal from "@/components/AlertConfigModal";
 import DiscordConfigModal from "@/components/DiscordConfigModal";
+import AdminAgents from "@/components/AdminAgents";
 import BulkActionBar from "@/components/BulkActionBar";
 import { DiscordIcon, TelegramIcon } from "@/components/BrandIcons";
 import type { User, AdminServer, AuditLogEntry } from "@/types/server";
@@ -206,11 +207,20 @@ interface SettingsTabProps {
   onSslDomainChange: (v: string) => void;
   maintenanceMode: boolean;
   onMaintenanceModeChange: (v: boolean) => void;
+  historyRetention: HistoryRetention;
+  onHistoryRetentionChange: (key: keyof HistoryRetention, v: number) => void;
   onSave: () => void;
   // eslint-disable-next-line @typescript-eslint/no-explicit-any
   t: any;
 }
 
+// History retention per resolution, days (0 = keep forever)
+interface HistoryRetention {
+  raw: number;
+  fiveMin: number;
+  hourly: number;
+}
+
 function resizeLogo(file: File, maxSize = 64): Promise<string> {
   return new Promise((resolve, reject) => {
     const img = new Image();
@@ -254,6 +264,7 @@ function SettingsTab({
   sslStatus, sslStatusLoading, forceHttps, onForceHttpsChange, onRefreshSsl,
   sslMode, sslDomain, onSslModeChange, onSslDomainChange,
   maintenanceMode, onMaintenanceModeChange,
+  historyRetention, onHistoryRetentionChange,
   onSave, t,
 }: SettingsTabProps) {
   const [showKey, setShowKey] = useState(false);
@@ -685,6 +696,7 @@ function SettingsTab({
             ["average_24h",  t.adminEmbedFieldAverage24h],
             ["unique_today", t.adminEmbedFieldUniqueToday],
             ["player_list",  t.adminEmbedFieldPlayerList],
+            ["rules",        t.adminEmbedFieldRules],
           ] as [string, string][]).map(([key, label]) => (
             <label key={key} className="flex items-center gap-2 cursor-pointer select-none text-sm text-foreground/80 hover:text-foreground transition-colors">
               <input
@@ -724,6 +736,34 @@ function SettingsTab({
         )}
       </div>
 
+      {/* History retention */}
+      <div className="glass-card p-4 flex flex-col gap-3">
+        <div className="flex items-center gap-2">
+          <Database className="w-4 h-4 text-neon-blue" />
+          <span className="text-sm font-semibold">{t.adminHistoryRetention}</span>
+        </div>
+        <div className="grid grid-cols-3 gap-3">
+          {([
+            ["raw",     t.adminHistoryRetentionRaw],
+            ["fiveMin", t.adminHistoryRetention5m],
+            ["hourly",  t.adminHistoryRetention1h],
+          ] as [keyof HistoryRetention, string][]).map(([key, label]) => (
+            <div key={key}>
+              <label className="text-xs text-muted-foreground mb-1 block">{label}</label>
+              <input
+                className={inputCls}
+                type="number"
+                min={0}
+                max={3650}
+                value={historyRetention[key]}
+                onChange={(e) => onHistoryRetentionChange(key, Math.min(3650, Math.max(0, parseInt(e.target.value) || 0)))}
+              />
+            </div>
+          ))}
+        </div>
+        <p className="text-xs text-muted-foreground">{t.adminHistoryRetentionHint}</p>
+      </div>
+
       {/* SSL / HTTPS */}
       <div className="glass-card p-4 flex flex-col gap-3">
         <div className="flex items-center justify-between">
@@ -1152,7 +1192,7 @@ export default function AdminPage() {
   const [discordProxy, setDiscordProxy] = useState("");
   const [discordAlertChannelID, setDiscordAlertChannelID] = useState("");
   const [discordRefreshInterval, setDiscordRefreshInterval] = useState(60);
-  const defaultEmbedCfg = { status: true, address: true, country: true, game: true, map: true, ping: true, players: true, peak_24h: true, uptime_24h: true, average_24h: true, unique_today: true, player_list: true };
+  const defaultEmbedCfg = { status: true, address: true, country: true, game: true, map: true, ping: true, players: true, peak_24h: true, uptime_24h: true, average_24h: true, unique_today: true, player_list: true, rules: true };
   const [discordEmbedCfg, setDiscordEmbedCfg] = useState<Record<string, boolean>>(defaultEmbedCfg);
   const [settingsVRisingMapEnabled, setSettingsVRisingMapEnabled] = useState(true);
   const [settingsVRisingHideAdmins, setSettingsVRisingHideAdmins] = useState(false);
@@ -1184,6 +1224,7 @@ export default function AdminPage() {
   const [healthLoading, setHealthLoading] = useState(false);
   const [healthCountdown, setHealthCountdown] = useState(15);
   const [settingsMaintenanceMode, setSettingsMaintenanceMode] = useState(false);
+  const [settingsHistoryRetention, setSettingsHistoryRetention] = useState<HistoryRetention>({ raw: 3, fiveMin: 30, hourly: 365 });
   const [settingsSaving, setSettingsSaving] = useState(false);
   const [settingsSaved, setSettingsSaved] = useState(false);
 
@@ -1336,6 +1377,11 @@ export default function AdminPage() {
         }
         setSettingsForceHttps(s.force_https ?? false);
         setSettingsMaintenanceMode(s.maintenance_mode ?? false);
+        setSettingsHistoryRetention({
+          raw: s.history_raw_retention_days ?? 3,
+          fiveMin: s.history_5m_retention_days ?? 30,
+          hourly: s.history_1h_retention_days ?? 365,
+        });
         setSettingsSslMode(s.ssl_mode || "none");
         setSettingsSslDomain(s.ssl_domain ?? "");
         setSettingsVRisingMapEnabled(s.vrising_map_enabled ?? true);
@@ -2231,6 +2277,7 @@ export default function AdminPage() {
                   { label: t.adminQaNews,        icon: <Newspaper className="w-3.5 h-3.5" />,     action: () => router.push("/admin/news") },
                   { label: t.exportServers,      icon: <Download className="w-3.5 h-3.5" />,      action: () => window.open("/api/v1/admin/export/servers.csv") },
                   { label: t.exportPlayers,      icon: <Download className="w-3.5 h-3.5" />,      action: () => window.open("/api/v1/admin/export/players.csv") },
+                  { label: t.exportSLAReport,    icon: <Download className="w-3.5 h-3.5" />,      action: () => window.open("/api/v1/admin/reports/sla?format=csv") },
                   { label: t.adminTabSettings,   icon: <Settings className="w-3.5 h-3.5" />,      action: () => setTab("settings") },
                   { label: t.adminQaAudit,       icon: <ClipboardList className="w-3.5 h-3.5" />, action: () => setTab("audit") },
                 ].map((btn) => (
@@ -2457,11 +2504,33 @@ export default function AdminPage() {
                     <p className="text-xs text-muted-foreground">{t.adminHealthGoVersion}</p>
                     <p className="font-medium text-xs font-mono">{health.go_version}</p>
                   </div>
+                  {health.poller && (
+                    <>
+                      <div className="space-y-0.5">
+                        <p className="text-xs text-muted-foreground">{t.adminHealthPollQueue}</p>
+                        <p className={`font-medium ${health.poller.scheduler.overdue > 0 ? "text-yellow-400" : ""}`}>
+                          {health.poller.scheduler.queue_depth} · {health.poller.scheduler.in_flight} {t.adminHealthPollInFlight}
+                        </p>
+                      </div>
+                      <div className="space-y-0.5">
+                        <p className="text-xs text-muted-foreground">{t.adminHealthPollDeferred}</p>
+                        <p className="text-xs">{health.poller.scheduler.dropped} {t.adminHealthPollDropped} · {health.poller.scheduler.rate_limited} {t.adminHealthPollRateLimited}</p>
+                      </div>
+                      <div className="space-y-0.5 col-span-2 sm:col-span-1">
+                        <p className="text-xs text-muted-foreground">{t.adminHealthPollLatency}</p>
+                        <p className="text-xs font-mono">
+                          {Object.entries(health.poller.protocols).map(([name, p]) => `${name} ${Math.round(p.avg_ms)}ms`).join(" · ") || "—"}
+                        </p>
+                      </div>
+                    </>
+                  )}
                 </div>
               ) : healthLoading ? (
                 <div className="grid grid-cols-3 gap-4">{Array.from({length:6}).map((_,i)=><div key={i} className="h-10 bg-white/5 rounded-lg animate-pulse"/>)}</div>
               ) : null}
             </div>
+
+            <AdminAgents />
           </div>
         )}
 
@@ -2830,6 +2899,8 @@ export default function AdminPage() {
             onSslDomainChange={setSettingsSslDomain}
             maintenanceMode={settingsMaintenanceMode}
             onMaintenanceModeChange={setSettingsMaintenanceMode}
+            historyRetention={settingsHistoryRetention}
+            onHistoryRetentionChange={(key, v) => setSettingsHistoryRetention((prev) => ({ ...prev, [key]: v }))}
             onRefreshSsl={() => {
               setSslStatusLoading(true);
               api.getSSLStatus().then((s) => setSslStatus(s)).catch(() => {}).finally(() => setSslStatusLoading(false));
@@ -2858,6 +2929,9 @@ export default function AdminPage() {
                   news_tg_thread_id: settingsNewsTGThreadId,
                   force_https: settingsForceHttps,
                   maintenance_mode: settingsMaintenanceMode,
+                  history_raw_retention_days: settingsHistoryRetention.raw,
+                  history_5m_retention_days: settingsHistoryRetention.fiveMin,
+                  history_1h_retention_days: settingsHistoryRetention.hourly,
                   ssl_mode: settingsSslMode,
                   ssl_domain: settingsSslDomain,
                   discord_bot_token: discordBotToken,
@@ -2,7 +2,7 @@
 
 import { useState, use } from "react";
 import { Users, Map, Wifi, ArrowLeft, ExternalLink, Star, Copy } from "lucide-react";
-import { useServer, useHistory, useLeaderboard, useServerPlayers } from "@/hooks/useServers";
+import { useServer, useHistory, useLeaderboard, useServerPlayers, useServerRegions } from "@/hooks/useServers";
 import { useUptime } from "@/hooks/useUptime";
 import { useLanguage } from "@/contexts/LanguageContext";
 import { useFavorites } from "@/hooks/useFavorites";
@@ -11,7 +11,10 @@ import { toast } from "@/lib/toast";
 import StatusIndicator from "@/components/StatusIndicator";
 import PlayerChart from "@/components/PlayerChart";
 import PlayerLeaderboard from "@/components/PlayerLeaderboard";
+import ServerIncidents from "@/components/ServerIncidents";
+import ServerMaintenance from "@/components/ServerMaintenance";
 import GameIcon from "@/components/GameIcon";
+import ServerBadges from "@/components/ServerBadges";
 import SiteBrand from "@/components/SiteBrand";
 import { ToastContainer } from "@/components/Toast";
 import type { LeaderboardEntry } from "@/types/server";
@@ -34,11 +37,12 @@ export default function ServerDetailPage({ params }: { params: Promise<{ id: str
   const serverId = parseInt(id, 10);
   const { t, locale } = useLanguage();
   const { favorites, toggle: toggleFavorite } = useFavorites();
-  const [tab, setTab] = useState<"history" | "leaderboard" | "players">("history");
+  const [tab, setTab] = useState<"history" | "leaderboard" | "players" | "incidents" | "maintenance">("history");
 
   const { data: server, isLoading } = useServer(serverId);
   const { data: uptimeData } = useUptime(serverId);
   const { data: leaderboard } = useLeaderboard(serverId, "7d");
+  const { data: regions } = useServerRegions(serverId);
   const status = server?.status;
   const online = status?.online_status ?? false;
 
@@ -131,6 +135,7 @@ export default function ServerDetailPage({ params }: { params: Promise<{ id: str
               </button>
               <div className="flex items-center gap-2 mt-1 text-xs text-muted-foreground flex-wrap">
                 <span>{gameTypeLabel(server.game_type)}</span>
+                <ServerBadges status={status} />
                 {server.country_code && (
                   <span className="flex items-center gap-1">
                     {countryFlag(server.country_code)} {server.country_name}
@@ -139,7 +144,7 @@ export default function ServerDetailPage({ params }: { params: Promise<{ id: str
               </div>
             </div>
             <div className="flex flex-col items-end gap-2">
-              <StatusIndicator online={online} showLabel />
+              <StatusIndicator online={online} maintenance={server.in_maintenance} showLabel />
               <div className="flex items-center gap-1">
                 <button
                   onClick={() => toggleFavorite(serverId)}
@@ -190,7 +195,7 @@ export default function ServerDetailPage({ params }: { params: Promise<{ id: str
 
           {/* Uptime + fill bar */}
           <div className="flex flex-col gap-3">
-            {uptimeData !== undefined && uptimeData.total > 0 && (
+            {uptimeData?.uptime_24h !== undefined && (
               <div className="flex items-center gap-2 text-xs">
                 <span className={cn(
                   "inline-flex items-center gap-1 px-2.5 py-1 rounded-full border text-xs font-medium",
@@ -202,7 +207,7 @@ export default function ServerDetailPage({ params }: { params: Promise<{ id: str
                 </span>
                 <span className="text-muted-foreground">{t.cardUptime}</span>
                 <span className="text-muted-foreground/50">
-                  {uptimeData.online}/{uptimeData.total} {locale === "ru" ? "проверок" : "checks"}
+                  {Math.round(uptimeData.downtime_seconds / 60)} {locale === "ru" ? "мин простоя" : "min downtime"}
                 </span>
               </div>
             )}
@@ -224,16 +229,36 @@ export default function ServerDetailPage({ params }: { params: Promise<{ id: str
                 </div>
               </div>
             )}
+            {regions && regions.length > 0 && (
+              <div className="flex items-center gap-2 text-xs flex-wrap">
+                <span className="text-muted-foreground">{t.serverDetailRegions}</span>
+                {regions.map((r) => (
+                  <span
+                    key={r.id}
+                    title={r.failure_reason}
+                    className={cn(
+                      "inline-flex items-center gap-1.5 px-2 py-0.5 rounded-full border font-mono",
+                      r.online_status ? "border-neon-green/30 text-neon-green" : "border-red-400/30 text-red-400",
+                    )}
+                  >
+                    <span className={cn("w-1.5 h-1.5 rounded-full", r.online_status ? "bg-neon-green" : "bg-red-400")} />
+                    {r.region}{r.online_status && ` · ${formatPing(r.ping_ms)}`}
+                  </span>
+                ))}
+              </div>
+            )}
           </div>
         </div>
 
         {/* Tabs */}
         <div className="flex gap-1 bg-white/5 rounded-xl p-1 w-fit">
-          {(["history", "leaderboard", "players"] as const).map((key) => {
+          {(["history", "leaderboard", "players", "incidents", "maintenance"] as const).map((key) => {
             const labels: Record<string, string> = {
               history: t.serverDetailHistory,
               leaderboard: t.serverDetailLeaderboard,
               players: t.serverDetailOnlinePlayers,
+              incidents: t.serverDetailIncidents,
+              maintenance: t.serverDetailMaintenance,
             };
             return (
               <button
@@ -254,6 +279,8 @@ export default function ServerDetailPage({ params }: { params: Promise<{ id: str
         <div className="glass-card rounded-2xl p-5">
           {tab === "history" && <PlayerChart serverId={serverId} />}
           {tab === "leaderboard" && <PlayerLeaderboard serverId={serverId} />}
+          {tab === "incidents" && <ServerIncidents serverId={serverId} ownerId={server?.owner_id} />}
+          {tab === "maintenance" && <ServerMaintenance serverId={serverId} ownerId={server?.owner_id} />}
           {tab === "players" && (
             <div className="flex flex-col gap-3">
               <p className="text-xs text-muted-foreground uppercase tracking-wide">
@@ -276,6 +303,15 @@ export default function ServerDetailPage({ params }: { params: Promise<{ id: str
                       className="px-3 py-1 rounded-lg bg-white/5 border border-white/10 text-sm text-foreground"
                     >
                       {p.name}
+                      {(p.score !== undefined || p.ping !== undefined || p.duration !== undefined) && (
+                        <span className="ml-2 text-xs text-muted-foreground font-mono">
+                          {[
+                            p.score !== undefined && `${p.score}`,
+                            p.ping !== undefined && `${p.ping}ms`,
+                            p.duration !== undefined && formatSeconds(p.duration),
+                          ].filter(Boolean).join(" · ")}
+                        </span>
+                      )}
                     </span>
                   ))}
                 </div>
@@ -1,11 +1,12 @@
 "use client";
 
-import { useState, useEffect } from "react";
-import { X, Plus, Save } from "lucide-react";
+import { useState, useEffect, useRef } from "react";
+import { X, Plus, Save, Radar } from "lucide-react";
 import { useCreateServer } from "@/hooks/useServers";
+import { api } from "@/lib/api";
 import { useLanguage } from "@/contexts/LanguageContext";
 import { GAME_META, gameTypeDefaultPort } from "@/lib/utils";
-import type { GameType, Server } from "@/types/server";
+import type { GameType, ProbeResult, Server } from "@/types/server";
 import GameIcon from "./GameIcon";
 
 interface AddEditServerModalProps {
@@ -32,12 +33,48 @@ export default function AddEditServerModal({ onClose, editServer, onUpdate }: Ad
     port: String(editServer?.port ?? 27015),
     game_type: (editServer?.game_type ?? "source") as GameType,
     secret_rcon_key: "",
+    rcon_port: editServer?.rcon_port ? String(editServer.rcon_port) : "",
     discord_color: editServer?.discord_color ?? "",
+    tracked_rules: editServer?.tracked_rules ?? "",
+    query_port: editServer?.query_port ? String(editServer.query_port) : "",
+    poll_interval: editServer?.poll_interval ? String(editServer.poll_interval) : "",
+    empty_poll_interval: editServer?.empty_poll_interval ? String(editServer.empty_poll_interval) : "",
   });
   const [error, setError] = useState("");
+  const [probing, setProbing] = useState(false);
+  const [probe, setProbe] = useState<ProbeResult | null>(null);
+
+  // Try every protocol on ip:port and prefill the game type from the fastest answer
+  const handleProbe = async () => {
+    if (!form.ip) { setError(t.fieldRequired); return; }
+    setError("");
+    setProbe(null);
+    setProbing(true);
+    try {
+      const results = await api.probeServer(form.ip, Number(form.port) || 0);
+      const hit = results.find((r) => r.games.includes(form.game_type)) ?? results[0];
+      if (!hit) { setError(t.probeNoResponse); return; }
+      setProbe(hit);
+      keepPort.current = !hit.games.includes(form.game_type);
+      setForm((f) => ({
+        ...f,
+        game_type: hit.games.includes(f.game_type) ? f.game_type : hit.games[0],
+        port: String(hit.port),
+        title: f.title || hit.server_name,
+      }));
+    } catch (err) {
+      setError((err as Error).message);
+    } finally {
+      setProbing(false);
+    }
+  };
+
+  // Set when the probe picks the game type, so its detected port is kept
+  const keepPort = useRef(false);
 
   // Auto-fill default port when game type changes (only in add mode)
   useEffect(() => {
+    if (keepPort.current) { keepPort.current = false; return; }
     if (!isEdit) {
       setForm((f) => ({ ...f, port: String(gameTypeDefaultPort(f.game_type)) }));
     }
@@ -48,7 +85,14 @@ export default function AddEditServerModal({ onClose, editServer, onUpdate }: Ad
     setError("");
     if (!form.ip) { setError(t.fieldRequired); return; }
 
-    const data = { ...form, port: Number(form.port) };
+    const data = {
+      ...form,
+      port: Number(form.port),
+      query_port: Number(form.query_port) || 0,
+      rcon_port: Number(form.rcon_port) || 0,
+      poll_interval: Number(form.poll_interval) || 0,
+      empty_poll_interval: Number(form.empty_poll_interval) || 0,
+    };
 
     if (isEdit && onUpdate) {
       onUpdate(data);
@@ -84,6 +128,24 @@ export default function AddEditServerModal({ onClose, editServer, onUpdate }: Ad
               <input className={field} type="number" min={1} max={65535} value={form.port} onChange={(e) => setForm({ ...form, port: e.target.value })} />
             </div>
           </div>
+          {!isEdit && (
+            <div className="flex items-center gap-2">
+              <button
+                type="button"
+                onClick={handleProbe}
+                disabled={probing}
+                className="flex items-center gap-1.5 text-xs text-muted-foreground hover:text-foreground border border-white/10 rounded-lg px-2.5 py-1.5 flex-shrink-0 transition-colors disabled:opacity-50"
+              >
+                <Radar className={`w-3.5 h-3.5 ${probing ? "animate-spin" : ""}`} />
+                {t.btnDetectGame}
+              </button>
+              {probe && (
+                <p className="text-xs text-muted-foreground truncate">
+                  {t.probeFound}: {probe.server_name || probe.protocol} · {probe.players_now}/{probe.players_max} · {probe.ping_ms}ms
+                </p>
+              )}
+            </div>
+          )}
           <div className="flex flex-col gap-1">
             <label className="text-xs text-muted-foreground uppercase tracking-wide">{t.fieldDisplayIp}</label>
             <input className={field} placeholder="play.myserver.com" value={form.display_ip} onChange={(e) => setForm({ ...form, display_ip: e.target.value })} />
@@ -102,9 +164,15 @@ export default function AddEditServerModal({ onClose, editServer, onUpdate }: Ad
               </select>
             </div>
           </div>
-          <div className="flex flex-col gap-1">
-            <label className="text-xs text-muted-foreground uppercase tracking-wide">{t.fieldRcon}</label>
-            <input className={field} placeholder="••••••••" type="password" value={form.secret_rcon_key} onChange={(e) => setForm({ ...form, secret_rcon_key: e.target.value })} />
+          <div className="grid grid-cols-3 gap-2">
+            <div className="flex flex-col gap-1 col-span-2">
+              <label className="text-xs text-muted-foreground uppercase tracking-wide">{t.fieldRcon}</label>
+              <input className={field} placeholder="••••••••" type="password" value={form.secret_rcon_key} onChange={(e) => setForm({ ...form, secret_rcon_key: e.target.value })} />
+            </div>
+            <div className="flex flex-col gap-1">
+              <label className="text-xs text-muted-foreground uppercase tracking-wide">{t.fieldRconPort}</label>
+              <input className={field} type="number" min={1} max={65535} placeholder={t.fieldRconPortAuto} value={form.rcon_port} onChange={(e) => setForm({ ...form, rcon_port: e.target.value })} />
+            </div>
           </div>
           <div className="flex flex-col gap-1">
             <label className="text-xs text-muted-foreground uppercase tracking-wide">{t.fieldDiscordColor}</label>
@@ -134,6 +202,26 @@ export default function AddEditServerModal({ onClose, editServer, onUpdate }: Ad
             </div>
             <p className="text-xs text-muted-foreground/60 leading-relaxed">{t.fieldDiscordColorHint}</p>
           </div>
+          <div className="flex flex-col gap-1">
+            <label className="text-xs text-muted-foreground uppercase tracking-wide">{t.fieldTrackedRules}</label>
+            <input className={field} placeholder="wipe,mapsize" value={form.tracked_rules} onChange={(e) => setForm({ ...form, tracked_rules: e.target.value })} />
+            <p className="text-xs text-muted-foreground/60 leading-relaxed">{t.fieldTrackedRulesHint}</p>
+          </div>
+          <div className="grid grid-cols-3 gap-2">
+            <div className="flex flex-col gap-1">
+              <label className="text-xs text-muted-foreground uppercase tracking-wide">{t.fieldQueryPort}</label>
+              <input className={field} type="number" min={1} max={65535} placeholder={form.port} value={form.query_port} onChange={(e) => setForm({ ...form, query_port: e.target.value })} />
+            </div>
+            <div className="flex flex-col gap-1">
+              <label className="text-xs text-muted-foreground uppercase tracking-wide">{t.fieldPollInterval}</label>
+              <input className={field} type="number" min={5} max={3600} placeholder="10" value={form.poll_interval} onChange={(e) => setForm({ ...form, poll_interval: e.target.value })} />
+            </div>
+            <div className="flex flex-col gap-1">
+              <label className="text-xs text-muted-foreground uppercase tracking-wide">{t.fieldEmptyPollInterval}</label>
+              <input className={field} type="number" min={5} max={3600} placeholder="60" value={form.empty_poll_interval} onChange={(e) => setForm({ ...form, empty_poll_interval: e.target.value })} />
+            </div>
+          </div>
+          <p className="text-xs text-muted-foreground/60 leading-relaxed -mt-2">{t.fieldPollHint}</p>
           {error && <p className="text-red-400 text-xs bg-red-400/10 rounded-lg px-3 py-2">{error}</p>}
           <div className="flex gap-2 pt-2">
             <button type="button" onClick={onClose} className="flex-1 px-4 py-2.5 rounded-xl text-sm text-muted-foreground hover:text-foreground border border-white/10 hover:border-white/20 transition-all">
@@ -0,0 +1,111 @@
+"use client";
+
+import { useState, useEffect } from "react";
+import { Radio, Plus, Trash2, Copy } from "lucide-react";
+import { api } from "@/lib/api";
+import { useLanguage } from "@/contexts/LanguageContext";
+import { toast } from "@/lib/toast";
+import { cn } from "@/lib/utils";
+import type { PollAgent } from "@/types/server";
+
+// An agent that has not reported for this long is shown as stale
+const AGENT_STALE_MS = 5 * 60_000;
+
+export default function AdminAgents() {
+  const { t } = useLanguage();
+  const [agents, setAgents] = useState<PollAgent[]>([]);
+  const [name, setName] = useState("");
+  const [region, setRegion] = useState("");
+  const [newToken, setNewToken] = useState("");
+  const [busy, setBusy] = useState(false);
+
+  const load = () => api.getAgents().then(setAgents).catch(() => {});
+  useEffect(() => { load(); }, []);
+
+  const create = async () => {
+    if (!name.trim() || !region.trim()) return;
+    setBusy(true);
+    try {
+      const res = await api.createAgent(name.trim(), region.trim());
+      setNewToken(res.token);
+      setName("");
+      setRegion("");
+      load();
+    } catch (err) {
+      toast((err as Error).message, "error");
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const toggle = async (a: PollAgent) => {
+    await api.updateAgent(a.id, { name: a.name, region: a.region, enabled: !a.enabled }).catch(() => {});
+    load();
+  };
+
+  const remove = async (a: PollAgent) => {
+    if (!confirm(`${t.adminAgentsDeleteConfirm} ${a.name}?`)) return;
+    await api.deleteAgent(a.id).catch(() => {});
+    load();
+  };
+
+  const input = "bg-white/5 border border-white/10 rounded-xl px-3 py-2 text-sm text-foreground outline-none focus:border-neon-green/50 transition-all placeholder:text-muted-foreground";
+
+  return (
+    <div className="glass-card rounded-2xl p-5 flex flex-col gap-4">
+      <h2 className="text-xs font-semibold uppercase tracking-wider text-muted-foreground flex items-center gap-2">
+        <Radio className="w-3.5 h-3.5" /> {t.adminAgentsTitle}
+      </h2>
+      <p className="text-xs text-muted-foreground/70 leading-relaxed">{t.adminAgentsHint}</p>
+
+      {agents.length > 0 && (
+        <div className="flex flex-col gap-1">
+          {agents.map((a) => {
+            const stale = !a.last_seen_at || Date.now() - new Date(a.last_seen_at).getTime() > AGENT_STALE_MS;
+            return (
+              <div key={a.id} className="flex items-center gap-3 py-1.5 px-2 rounded-lg hover:bg-white/5 transition-colors text-sm">
+                <span className={cn("w-2 h-2 rounded-full flex-shrink-0", !a.enabled ? "bg-muted-foreground/30" : stale ? "bg-yellow-400" : "bg-neon-green")} />
+                <span className="font-medium text-foreground truncate">{a.name}</span>
+                <span className="text-xs font-mono text-muted-foreground">{a.region}</span>
+                <span className="flex-1 text-xs text-muted-foreground/60 truncate">
+                  {a.last_seen_at ? `${t.adminAgentsLastSeen} ${new Date(a.last_seen_at).toLocaleString()} · ${a.last_ip}` : t.adminAgentsNeverSeen}
+                </span>
+                <button onClick={() => toggle(a)} className="text-xs text-muted-foreground hover:text-foreground transition-colors">
+                  {a.enabled ? t.adminAgentsDisable : t.adminAgentsEnable}
+                </button>
+                <button onClick={() => remove(a)} className="text-muted-foreground hover:text-red-400 transition-colors">
+                  <Trash2 className="w-3.5 h-3.5" />
+                </button>
+              </div>
+            );
+          })}
+        </div>
+      )}
+
+      <div className="flex flex-wrap gap-2">
+        <input className={cn(input, "flex-1 min-w-[8rem]")} placeholder={t.adminAgentsName} value={name} onChange={(e) => setName(e.target.value)} maxLength={64} />
+        <input className={cn(input, "w-32")} placeholder={t.adminAgentsRegion} value={region} onChange={(e) => setRegion(e.target.value)} maxLength={32} />
+        <button
+          onClick={create}
+          disabled={busy || !name.trim() || !region.trim()}
+          className="flex items-center gap-1.5 px-3 py-2 rounded-xl text-xs font-semibold bg-neon-green/20 text-neon-green border border-neon-green/30 hover:bg-neon-green/30 transition-all disabled:opacity-50"
+        >
+          <Plus className="w-3.5 h-3.5" /> {t.adminAgentsAdd}
+        </button>
+      </div>
+
+      {newToken && (
+        <div className="flex flex-col gap-1 bg-yellow-400/10 border border-yellow-400/20 rounded-xl px-3 py-2">
+          <p className="text-xs text-yellow-400">{t.adminAgentsTokenOnce}</p>
+          <button
+            onClick={() => navigator.clipboard.writeText(newToken).then(() => toast(t.adminAgentsTokenCopied))}
+            className="flex items-center gap-2 text-xs font-mono text-foreground break-all text-left"
+          >
+            {newToken}
+            <Copy className="w-3 h-3 flex-shrink-0 opacity-60" />
+          </button>
+        </div>
+      )}
+    </div>
+  );
+}
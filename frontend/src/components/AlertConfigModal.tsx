@@ -5,6 +5,7 @@ import { X, Bell } from "lucide-react";
 import { api } from "@/lib/api";
 import { useLanguage } from "@/contexts/LanguageContext";
 import { toast } from "@/lib/toast";
+import AlertRulesEditor from "@/components/AlertRulesEditor";
 import type { AlertConfig } from "@/types/server";
 
 interface AlertConfigModalProps {
@@ -56,7 +57,7 @@ export default function AlertConfigModal({ serverID, serverName, onClose }: Aler
 
   return (
     <div className="fixed inset-0 z-50 flex items-center justify-center p-4 bg-black/60 backdrop-blur-sm">
-      <div className="glass-card rounded-2xl p-6 w-full max-w-md relative animate-slide-up">
+      <div className="glass-card rounded-2xl p-6 w-full max-w-lg max-h-[90vh] overflow-y-auto relative animate-slide-up">
         <button
           onClick={onClose}
           className="absolute top-4 right-4 text-muted-foreground hover:text-foreground transition-colors"
@@ -146,6 +147,8 @@ export default function AlertConfigModal({ serverID, serverName, onClose }: Aler
                 ✕
               </button>
             </div>
+
+            <AlertRulesEditor serverID={serverID} />
           </div>
         )}
       </div>
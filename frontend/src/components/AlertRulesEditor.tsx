@@ -0,0 +1,176 @@
+"use client";
+
+import { useState, useEffect } from "react";
+import { Plus, Trash2 } from "lucide-react";
+import { api } from "@/lib/api";
+import { useLanguage } from "@/contexts/LanguageContext";
+import { toast } from "@/lib/toast";
+import type { AlertRule, AlertRuleCondition, AlertRuleInput } from "@/types/server";
+
+const CONDITIONS: AlertRuleCondition[] = [
+  "players_above",
+  "players_below",
+  "ping_above",
+  "server_full",
+  "map_changed",
+  "name_changed",
+  "version_changed",
+];
+
+// Conditions compared against rule.threshold; the rest fire on a change or on their own
+const THRESHOLD_CONDITIONS: AlertRuleCondition[] = ["players_above", "players_below", "ping_above"];
+
+const EMPTY_RULE: AlertRuleInput = {
+  name: "",
+  condition: "players_above",
+  threshold: 0,
+  duration_min: 0,
+  cooldown_min: 30,
+  enabled: true,
+  tg_chat_id: "",
+  email_to: "",
+  webhook_url: "",
+};
+
+const toInput = (r: AlertRule): AlertRuleInput => ({
+  name: r.name,
+  condition: r.condition,
+  threshold: r.threshold,
+  duration_min: r.duration_min,
+  cooldown_min: r.cooldown_min,
+  enabled: r.enabled,
+  tg_chat_id: r.tg_chat_id,
+  email_to: r.email_to,
+  webhook_url: r.webhook_url,
+});
+
+export default function AlertRulesEditor({ serverID }: { serverID: number }) {
+  const { t } = useLanguage();
+  const [rules, setRules] = useState<AlertRule[]>([]);
+  const [draft, setDraft] = useState<AlertRuleInput>(EMPTY_RULE);
+  const [busy, setBusy] = useState(false);
+
+  useEffect(() => {
+    api.getAlertRules(serverID).then(setRules).catch(() => setRules([]));
+  }, [serverID]);
+
+  const conditionLabel: Record<AlertRuleCondition, string> = {
+    players_above: t.alertRulePlayersAbove,
+    players_below: t.alertRulePlayersBelow,
+    ping_above: t.alertRulePingAbove,
+    server_full: t.alertRuleServerFull,
+    map_changed: t.alertRuleMapChanged,
+    name_changed: t.alertRuleNameChanged,
+    version_changed: t.alertRuleVersionChanged,
+  };
+
+  const create = async () => {
+    setBusy(true);
+    try {
+      const rule = await api.createAlertRule(serverID, draft);
+      setRules((rs) => [...rs, rule]);
+      setDraft(EMPTY_RULE);
+    } catch (err) {
+      toast((err as Error).message, "error");
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const toggle = async (r: AlertRule) => {
+    try {
+      const updated = await api.updateAlertRule(serverID, r.id, { ...toInput(r), enabled: !r.enabled });
+      setRules((rs) => rs.map((x) => (x.id === r.id ? updated : x)));
+    } catch (err) {
+      toast((err as Error).message, "error");
+    }
+  };
+
+  const remove = async (r: AlertRule) => {
+    if (!confirm(t.alertRuleDeleteConfirm)) return;
+    try {
+      await api.deleteAlertRule(serverID, r.id);
+      setRules((rs) => rs.filter((x) => x.id !== r.id));
+    } catch (err) {
+      toast((err as Error).message, "error");
+    }
+  };
+
+  const set = <K extends keyof AlertRuleInput>(key: K, value: AlertRuleInput[K]) =>
+    setDraft((d) => ({ ...d, [key]: value }));
+
+  const input = "bg-white/5 border border-white/10 rounded-lg px-3 py-2 text-sm text-foreground placeholder-muted-foreground focus:outline-none focus:border-neon-blue/50 transition-colors";
+  const hasThreshold = THRESHOLD_CONDITIONS.includes(draft.condition);
+
+  return (
+    <div className="flex flex-col gap-3 border-t border-white/10 pt-4">
+      <p className="text-xs text-muted-foreground uppercase tracking-wide">{t.alertRulesTitle}</p>
+
+      {rules.length === 0 ? (
+        <p className="text-sm text-muted-foreground">{t.alertRulesNone}</p>
+      ) : (
+        <div className="flex flex-col gap-1">
+          {rules.map((r) => (
+            <div key={r.id} className="flex items-center gap-2 py-1.5 px-2 rounded-lg hover:bg-white/5 transition-colors text-sm">
+              <div
+                onClick={() => toggle(r)}
+                className={`w-8 h-4 rounded-full transition-colors cursor-pointer flex-shrink-0 ${r.enabled ? "bg-neon-green" : "bg-white/20"}`}
+              >
+                <div className={`w-4 h-4 rounded-full bg-white shadow transition-transform ${r.enabled ? "translate-x-4" : "translate-x-0"}`} />
+              </div>
+              <span className="flex-1 min-w-0 truncate text-foreground">
+                {r.name || conditionLabel[r.condition]}
+                {THRESHOLD_CONDITIONS.includes(r.condition) && <span className="text-muted-foreground"> · {r.threshold}</span>}
+                {r.duration_min > 0 && <span className="text-muted-foreground"> · {r.duration_min} {t.alertRuleMinutes}</span>}
+              </span>
+              {r.last_fired_at && (
+                <span className="text-xs text-muted-foreground/60 font-mono" title={t.alertRuleLastFired}>
+                  {new Date(r.last_fired_at).toLocaleString()}
+                </span>
+              )}
+              <button onClick={() => remove(r)} className="text-muted-foreground hover:text-red-400 transition-colors">
+                <Trash2 className="w-3.5 h-3.5" />
+              </button>
+            </div>
+          ))}
+        </div>
+      )}
+
+      <div className="grid grid-cols-2 gap-2">
+        <select className={input} value={draft.condition} onChange={(e) => set("condition", e.target.value as AlertRuleCondition)}>
+          {CONDITIONS.map((c) => (
+            <option key={c} value={c}>{conditionLabel[c]}</option>
+          ))}
+        </select>
+        <input className={input} placeholder={t.alertRuleName} value={draft.name} maxLength={100} onChange={(e) => set("name", e.target.value)} />
+        {hasThreshold && (
+          <label className="flex flex-col gap-1 text-xs text-muted-foreground">
+            {t.alertRuleThreshold}
+            <input type="number" min={0} className={input} value={draft.threshold} onChange={(e) => set("threshold", Number(e.target.value))} />
+          </label>
+        )}
+        {!draft.condition.endsWith("_changed") && (
+          <label className="flex flex-col gap-1 text-xs text-muted-foreground">
+            {t.alertRuleDuration}
+            <input type="number" min={0} max={1440} className={input} value={draft.duration_min} onChange={(e) => set("duration_min", Number(e.target.value))} />
+          </label>
+        )}
+        <label className="flex flex-col gap-1 text-xs text-muted-foreground">
+          {t.alertRuleCooldown}
+          <input type="number" min={0} max={10080} className={input} value={draft.cooldown_min} onChange={(e) => set("cooldown_min", Number(e.target.value))} />
+        </label>
+        <input className={input} placeholder={t.alertsTgChatId} value={draft.tg_chat_id} onChange={(e) => set("tg_chat_id", e.target.value)} />
+        <input type="email" className={input} placeholder={t.alertsEmailTo} value={draft.email_to} onChange={(e) => set("email_to", e.target.value)} />
+        <input className={`${input} col-span-2`} placeholder={t.alertRuleWebhook} value={draft.webhook_url} onChange={(e) => set("webhook_url", e.target.value)} />
+      </div>
+      <span className="text-xs text-muted-foreground">{t.alertRuleTargetsHint}</span>
+      <button
+        onClick={create}
+        disabled={busy}
+        className="flex items-center justify-center gap-1.5 py-2 rounded-lg text-xs border border-white/10 hover:border-white/25 hover:bg-white/5 text-muted-foreground hover:text-foreground transition-all disabled:opacity-50"
+      >
+        <Plus className="w-3.5 h-3.5" /> {t.alertRuleAdd}
+      </button>
+    </div>
+  );
+}
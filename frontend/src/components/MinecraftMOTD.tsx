@@ -0,0 +1,59 @@
+"use client";
+
+import type { MOTDSpan } from "@/types/server";
+
+// Minecraft formatting code colors (§0–§f)
+const MC_COLORS: Record<string, string> = {
+  black: "#000000",
+  dark_blue: "#0000AA",
+  dark_green: "#00AA00",
+  dark_aqua: "#00AAAA",
+  dark_red: "#AA0000",
+  dark_purple: "#AA00AA",
+  gold: "#FFAA00",
+  gray: "#AAAAAA",
+  dark_gray: "#555555",
+  blue: "#5555FF",
+  green: "#55FF55",
+  aqua: "#55FFFF",
+  red: "#FF5555",
+  light_purple: "#FF55FF",
+  yellow: "#FFFF55",
+  white: "#FFFFFF",
+};
+
+export function parseMOTDSpans(raw?: string): MOTDSpan[] {
+  if (!raw) return [];
+  try {
+    const spans = JSON.parse(raw);
+    return Array.isArray(spans) ? spans : [];
+  } catch {
+    return [];
+  }
+}
+
+interface MinecraftMOTDProps {
+  spans: MOTDSpan[];
+  className?: string;
+}
+
+export default function MinecraftMOTD({ spans, className }: MinecraftMOTDProps) {
+  const plain = spans.map((s) => s.text).join("");
+  return (
+    <p className={className} title={plain}>
+      {spans.map((s, i) => (
+        <span
+          key={i}
+          style={{
+            color: s.color ? (MC_COLORS[s.color] ?? (s.color.startsWith("#") ? s.color : undefined)) : undefined,
+            fontWeight: s.bold ? 700 : undefined,
+            fontStyle: s.italic ? "italic" : undefined,
+            textDecoration: [s.underlined && "underline", s.strikethrough && "line-through"].filter(Boolean).join(" ") || undefined,
+          }}
+        >
+          {s.text}
+        </span>
+      ))}
+    </p>
+  );
+}
@@ -4,11 +4,11 @@ import { useState, useRef, useEffect, useCallback } from "react";
 import { Terminal, X, Send } from "lucide-react";
 import { cn } from "@/lib/utils";
 import { useLanguage } from "@/contexts/LanguageContext";
+import { useAuth } from "@/contexts/AuthContext";
 
 interface RconConsoleProps {
   serverId: number;
   serverTitle: string;
-  apiKey: string;
   onClose: () => void;
 }
 
@@ -23,8 +23,9 @@ const WS_URL =
         `${window.location.protocol === "https:" ? "wss" : "ws"}://${window.location.host}`)
     : "";
 
-export default function RconConsole({ serverId, serverTitle, apiKey, onClose }: RconConsoleProps) {
+export default function RconConsole({ serverId, serverTitle, onClose }: RconConsoleProps) {
   const { t } = useLanguage();
+  const { token } = useAuth();
   const [logs, setLogs] = useState<LogLine[]>([{ type: "system", text: `Connecting to ${serverTitle}…` }]);
   const [input, setInput] = useState("");
   const [connected, setConnected] = useState(false);
@@ -36,7 +37,7 @@ export default function RconConsole({ serverId, serverTitle, apiKey, onClose }:
   }, []);
 
   useEffect(() => {
-    const ws = new WebSocket(`${WS_URL}/api/v1/rcon?key=${apiKey}`);
+    const ws = new WebSocket(`${WS_URL}/api/v1/rcon?token=${encodeURIComponent(token ?? "")}`);
     wsRef.current = ws;
     ws.onopen = () => ws.send(JSON.stringify({ server_id: serverId }));
     ws.onmessage = (event) => {
@@ -50,7 +51,7 @@ export default function RconConsole({ serverId, serverTitle, apiKey, onClose }:
     ws.onclose = () => { setConnected(false); addLog("system", "Connection closed."); };
     ws.onerror = () => addLog("system", "WebSocket error.");
     return () => ws.close();
-  }, [serverId, apiKey, addLog]);
+  }, [serverId, token, addLog]);
 
   useEffect(() => { logsEndRef.current?.scrollIntoView({ behavior: "smooth" }); }, [logs]);
 
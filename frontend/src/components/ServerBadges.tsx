@@ -0,0 +1,41 @@
+"use client";
+
+import { Lock, ShieldCheck, Bot } from "lucide-react";
+import { cn } from "@/lib/utils";
+import { useLanguage } from "@/contexts/LanguageContext";
+import type { ServerStatus } from "@/types/server";
+
+// Version, password, VAC and bot badges from the A2S_INFO extra data; renders nothing
+// for protocols that don't report them
+export default function ServerBadges({ status, className }: { status?: ServerStatus | null; className?: string }) {
+  const { t } = useLanguage();
+  if (!status?.online_status) return null;
+  const bots = status.bots ?? 0;
+  if (!status.version && !status.password && !status.vac && bots === 0) return null;
+
+  const badge = "inline-flex items-center gap-1 px-1.5 py-0.5 rounded-md bg-white/5 border border-white/10";
+  return (
+    <span className={cn("inline-flex items-center gap-1 flex-wrap", className)}>
+      {status.version && (
+        <span className={cn(badge, "font-mono truncate max-w-[120px]")} title={`${t.serverVersion}: ${status.version}`}>
+          {status.version}
+        </span>
+      )}
+      {status.password && (
+        <span className={cn(badge, "text-yellow-400")} title={t.serverPassword}>
+          <Lock className="w-3 h-3" />
+        </span>
+      )}
+      {status.vac && (
+        <span className={cn(badge, "text-neon-green")} title={t.serverVAC}>
+          <ShieldCheck className="w-3 h-3" /> VAC
+        </span>
+      )}
+      {bots > 0 && (
+        <span className={badge} title={t.serverBots}>
+          <Bot className="w-3 h-3" /> {bots}
+        </span>
+      )}
+    </span>
+  );
+}
@@ -2,7 +2,7 @@
 
 import { useState } from "react";
 import { Users, Map, Wifi, Terminal, ExternalLink, Trash2, BarChart2, Trophy, Pencil, Star, Copy, Share2 } from "lucide-react";
-import { cn, formatPlayers, formatPing, buildJoinLink, gameTypeLabel } from "@/lib/utils";
+import { cn, formatPlayers, formatPing, buildJoinLink, gameTypeLabel, FAILURE_REASON_KEYS } from "@/lib/utils";
 import { useLanguage } from "@/contexts/LanguageContext";
 import { useSiteSettings } from "@/contexts/SiteSettingsContext";
 import { useServerPlayers, useHistory } from "@/hooks/useServers";
@@ -12,6 +12,8 @@ import PlayerChart from "./PlayerChart";
 import PlayerLeaderboard from "./PlayerLeaderboard";
 import RconConsole from "./RconConsole";
 import GameIcon from "./GameIcon";
+import ServerBadges from "./ServerBadges";
+import MinecraftMOTD, { parseMOTDSpans } from "./MinecraftMOTD";
 import VRisingMap from "./VRisingMap";
 import type { Server } from "@/types/server";
 import { useUptime } from "@/hooks/useUptime";
@@ -47,6 +49,7 @@ function gameTypeColor(g: Server["game_type"]): string {
     case "minecraft":
     case "minecraft_bedrock":  return "linear-gradient(90deg,#4ade80,#16a34a)";
     case "fivem":              return "linear-gradient(90deg,#3b82f6,#1d4ed8)";
+    case "redm":               return "linear-gradient(90deg,#dc2626,#7f1d1d)";
     case "samp":               return "linear-gradient(90deg,#f97316,#c2410c)";
     case "valheim":            return "linear-gradient(90deg,#a78bfa,#7c3aed)";
     case "terraria":           return "linear-gradient(90deg,#86efac,#22c55e)";
@@ -61,7 +64,7 @@ function gameTypeColor(g: Server["game_type"]): string {
 
 const PLAYER_SUPPORTED: Server["game_type"][] = [
   "source", "fivem", "gmod", "valheim", "dayz", "squad", "vrising", "terraria",
-  "samp", "minecraft", "minecraft_bedrock",
+  "samp", "minecraft", "minecraft_bedrock", "redm",
 ];
 
 export default function ServerCard({ server, onDelete, onEdit, isFavorite, onToggleFavorite, compact }: ServerCardProps) {
@@ -86,6 +89,8 @@ export default function ServerCard({ server, onDelete, onEdit, isFavorite, onTog
   const motd = isMinecraft && status?.server_name
     ? status.server_name.replace(/§./g, "").trim()
     : null;
+  const motdSpans = isMinecraft && online ? parseMOTDSpans(status?.motd_spans) : [];
+  const [faviconFailed, setFaviconFailed] = useState(false);
 
   const fillRatio = online && status?.players_max ? status.players_now / status.players_max : 0;
   const fillRing =
@@ -172,7 +177,7 @@ export default function ServerCard({ server, onDelete, onEdit, isFavorite, onTog
               {server.display_ip || server.ip}:{server.port}
             </button>
           </div>
-          <StatusIndicator online={online} showLabel />
+          <StatusIndicator online={online} maintenance={server.in_maintenance} showLabel />
           <div className="hidden sm:flex flex-col items-center min-w-[56px] gap-0.5">
             <span className="text-sm font-bold text-foreground">{online ? formatPlayers(status!.players_now, status!.players_max) : "—"}</span>
             {online && status!.players_max > 0 && (
@@ -199,7 +204,7 @@ export default function ServerCard({ server, onDelete, onEdit, isFavorite, onTog
             <span className="text-sm font-bold text-foreground truncate max-w-full px-1">{online && status?.current_map ? status.current_map : "—"}</span>
             <span className="text-xs text-muted-foreground">{t.cardMap}</span>
           </div>
-          {uptimeData !== undefined && uptimeData.total > 0 && (
+          {uptimeData?.uptime_24h !== undefined && (
             <div className="hidden xl:block flex-shrink-0">
               <span className={cn(
                 "inline-flex items-center gap-1 px-2 py-0.5 rounded-full border text-xs font-medium",
@@ -235,7 +240,7 @@ export default function ServerCard({ server, onDelete, onEdit, isFavorite, onTog
             )}
           </div>
         </div>
-        {rconOpen && <RconConsole serverId={server.id} serverTitle={server.title} apiKey={process.env.NEXT_PUBLIC_API_SECRET_KEY ?? ""} onClose={() => setRconOpen(false)} />}
+        {rconOpen && <RconConsole serverId={server.id} serverTitle={server.title} onClose={() => setRconOpen(false)} />}
       </>
     );
   }
@@ -257,7 +262,16 @@ export default function ServerCard({ server, onDelete, onEdit, isFavorite, onTog
         <div className="flex items-start justify-between gap-3">
           <div className="flex-1 min-w-0">
             <div className="flex items-center gap-2 mb-1">
-              <GameIcon gameType={server.game_type} />
+              {isMinecraft && !faviconFailed ? (
+                <img
+                  src={`${process.env.NEXT_PUBLIC_API_URL ?? ""}/api/v1/servers/${server.id}/favicon`}
+                  alt=""
+                  className="w-6 h-6 rounded-sm flex-shrink-0 [image-rendering:pixelated]"
+                  onError={() => setFaviconFailed(true)}
+                />
+              ) : (
+                <GameIcon gameType={server.game_type} />
+              )}
               <h3 className="font-bold text-base truncate text-foreground">
                 {server.title || status?.server_name || server.ip}
               </h3>
@@ -281,17 +295,25 @@ export default function ServerCard({ server, onDelete, onEdit, isFavorite, onTog
             {!isMinecraft && serverNameDiffers && (
               <p className="text-xs text-muted-foreground/70 italic truncate mt-0.5">{status!.server_name}</p>
             )}
-            {motd && motd !== server.title && (
+            {motdSpans.length > 0 && motd !== server.title ? (
+              <MinecraftMOTD spans={motdSpans} className="text-xs truncate mt-0.5" />
+            ) : motd && motd !== server.title && (
               <p className="text-xs text-muted-foreground/70 italic truncate mt-0.5" title={motd}>{motd}</p>
             )}
-            <p className="text-xs text-muted-foreground mt-0.5">{gameTypeLabel(server.game_type)}</p>
+            <div className="flex items-center gap-1.5 text-xs text-muted-foreground mt-0.5 flex-wrap">
+              <span>{gameTypeLabel(server.game_type)}</span>
+              <ServerBadges status={status} />
+            </div>
           </div>
           <div className="flex flex-col items-end gap-1">
             <div title={lastUpdateTitle}>
-              <StatusIndicator online={online} showLabel />
+              <StatusIndicator online={online} maintenance={server.in_maintenance} showLabel />
             </div>
             {!online && status?.last_update && (
-              <span className="text-xs text-muted-foreground/60">
+              <span
+                className="text-xs text-muted-foreground/60"
+                title={status.failure_reason ? t[FAILURE_REASON_KEYS[status.failure_reason]] : undefined}
+              >
                 {timeSince(status.last_update, locale)} {t.offlineSince}
               </span>
             )}
@@ -385,7 +407,7 @@ export default function ServerCard({ server, onDelete, onEdit, isFavorite, onTog
         </div>
 
         {/* Uptime badge */}
-        {uptimeData !== undefined && uptimeData.total > 0 && (
+        {uptimeData?.uptime_24h !== undefined && (
           <div className="flex items-center gap-1.5 text-xs text-muted-foreground">
             <span className={cn(
               "inline-flex items-center gap-1 px-2 py-0.5 rounded-full border text-xs font-medium",
@@ -545,7 +567,7 @@ export default function ServerCard({ server, onDelete, onEdit, isFavorite, onTog
           )}
         </div>
       </div>
-      {rconOpen && <RconConsole serverId={server.id} serverTitle={server.title} apiKey={process.env.NEXT_PUBLIC_API_SECRET_KEY ?? ""} onClose={() => setRconOpen(false)} />}
+      {rconOpen && <RconConsole serverId={server.id} serverTitle={server.title} onClose={() => setRconOpen(false)} />}
     </>
   );
 }
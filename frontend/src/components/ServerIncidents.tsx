@@ -0,0 +1,123 @@
+"use client";
+
+import { useState } from "react";
+import { useQueryClient } from "@tanstack/react-query";
+import { CheckCircle2 } from "lucide-react";
+import { api } from "@/lib/api";
+import { useLanguage } from "@/contexts/LanguageContext";
+import { useAuth } from "@/contexts/AuthContext";
+import { useServerIncidents } from "@/hooks/useServers";
+import { cn, FAILURE_REASON_KEYS } from "@/lib/utils";
+import { toast } from "@/lib/toast";
+import type { ServerIncident } from "@/types/server";
+
+const PERIODS = ["7d", "30d", "90d"] as const;
+
+function formatDuration(sec: number | null): string {
+  if (sec === null) return "—";
+  const d = Math.floor(sec / 86400);
+  const h = Math.floor((sec % 86400) / 3600);
+  const m = Math.floor((sec % 3600) / 60);
+  if (d > 0) return `${d}d ${h}h`;
+  if (h > 0) return `${h}h ${m}m`;
+  return `${Math.max(m, sec > 0 ? 1 : 0)}m`;
+}
+
+interface ServerIncidentsProps {
+  serverId: number;
+  ownerId?: number;
+}
+
+export default function ServerIncidents({ serverId, ownerId }: ServerIncidentsProps) {
+  const { t } = useLanguage();
+  const { user } = useAuth();
+  const qc = useQueryClient();
+  const [period, setPeriod] = useState<(typeof PERIODS)[number]>("30d");
+  const { data, isLoading } = useServerIncidents(serverId, period);
+  const canAck = !!user && (user.role === "admin" || user.id === ownerId);
+
+  const ack = async (inc: ServerIncident) => {
+    const note = prompt(t.incidentsAckNotePrompt);
+    if (note === null) return;
+    try {
+      await api.ackIncident(serverId, inc.id, note);
+      qc.invalidateQueries({ queryKey: ["incidents", serverId] });
+    } catch (err) {
+      toast((err as Error).message, "error");
+    }
+  };
+
+  const stats = data?.stats;
+  const figures: [string, string][] = stats ? [
+    [t.incidentsCount, String(stats.incidents)],
+    [t.incidentsDowntime, formatDuration(stats.downtime_seconds)],
+    [t.incidentsMTTR, formatDuration(stats.mttr_seconds)],
+    [t.incidentsMTBF, formatDuration(stats.mtbf_seconds)],
+  ] : [];
+
+  return (
+    <div className="flex flex-col gap-4">
+      <div className="flex items-center justify-between">
+        <p className="text-xs text-muted-foreground uppercase tracking-wide">{t.serverDetailIncidents}</p>
+        <div className="flex gap-1 bg-white/5 rounded-lg p-0.5">
+          {PERIODS.map((p) => (
+            <button
+              key={p}
+              onClick={() => setPeriod(p)}
+              className={cn(
+                "px-2 py-0.5 rounded-md text-xs transition-all",
+                period === p ? "bg-white/10 text-foreground" : "text-muted-foreground hover:text-foreground",
+              )}
+            >
+              {p}
+            </button>
+          ))}
+        </div>
+      </div>
+
+      {figures.length > 0 && (
+        <div className="grid grid-cols-2 sm:grid-cols-4 gap-2">
+          {figures.map(([label, value]) => (
+            <div key={label} className="bg-white/5 rounded-xl px-3 py-2">
+              <p className="text-[11px] text-muted-foreground">{label}</p>
+              <p className="text-sm font-semibold font-mono text-foreground">{value}</p>
+            </div>
+          ))}
+        </div>
+      )}
+
+      {isLoading ? (
+        <div className="h-20 bg-white/5 rounded-xl animate-pulse" />
+      ) : !data || data.items.length === 0 ? (
+        <p className="text-sm text-muted-foreground">{t.incidentsNone}</p>
+      ) : (
+        <div className="flex flex-col gap-1">
+          {data.items.map((inc) => (
+            <div key={inc.id} className="flex items-center gap-3 py-1.5 px-2 rounded-lg hover:bg-white/5 transition-colors text-sm">
+              <span className={cn("w-2 h-2 rounded-full flex-shrink-0", inc.resolved_at ? "bg-muted-foreground/40" : "bg-red-400 animate-pulse")} />
+              <span className="text-xs text-muted-foreground font-mono whitespace-nowrap">
+                {new Date(inc.started_at).toLocaleString()}
+              </span>
+              <span className={cn("text-xs font-mono", inc.resolved_at ? "text-foreground" : "text-red-400")}>
+                {inc.resolved_at ? formatDuration(inc.duration_sec) : t.incidentsOngoing}
+              </span>
+              <span className="flex-1 text-xs text-muted-foreground truncate">
+                {inc.cause ? t[FAILURE_REASON_KEYS[inc.cause]] : ""}
+              </span>
+              {inc.ack_at ? (
+                <span className="flex items-center gap-1 text-xs text-neon-green/80 truncate max-w-[40%]" title={inc.ack_note}>
+                  <CheckCircle2 className="w-3.5 h-3.5 flex-shrink-0" />
+                  {inc.ack_username}{inc.ack_note && ` · ${inc.ack_note}`}
+                </span>
+              ) : canAck && (
+                <button onClick={() => ack(inc)} className="text-xs text-muted-foreground hover:text-foreground transition-colors">
+                  {t.incidentsAck}
+                </button>
+              )}
+            </div>
+          ))}
+        </div>
+      )}
+    </div>
+  );
+}
@@ -0,0 +1,123 @@
+"use client";
+
+import { useState } from "react";
+import { useQueryClient } from "@tanstack/react-query";
+import { Plus, Trash2, Wrench } from "lucide-react";
+import { api } from "@/lib/api";
+import { useLanguage } from "@/contexts/LanguageContext";
+import { useAuth } from "@/contexts/AuthContext";
+import { useMaintenanceWindows } from "@/hooks/useServers";
+import { cn } from "@/lib/utils";
+import { toast } from "@/lib/toast";
+import type { MaintenanceRecurrence, MaintenanceWindow } from "@/types/server";
+
+interface ServerMaintenanceProps {
+  serverId: number;
+  ownerId?: number;
+}
+
+export default function ServerMaintenance({ serverId, ownerId }: ServerMaintenanceProps) {
+  const { t } = useLanguage();
+  const { user } = useAuth();
+  const qc = useQueryClient();
+  const { data: windows = [], isLoading } = useMaintenanceWindows(serverId);
+  const canManage = !!user && (user.role === "admin" || user.id === ownerId);
+
+  const [title, setTitle] = useState("");
+  const [startsAt, setStartsAt] = useState("");
+  const [duration, setDuration] = useState(30);
+  const [recurrence, setRecurrence] = useState<MaintenanceRecurrence>("");
+  const [busy, setBusy] = useState(false);
+
+  const refresh = () => {
+    qc.invalidateQueries({ queryKey: ["maintenance", serverId] });
+    qc.invalidateQueries({ queryKey: ["servers", serverId] });
+  };
+
+  const create = async () => {
+    if (!startsAt) return;
+    setBusy(true);
+    try {
+      // datetime-local has no zone: it is the browser's local time
+      await api.createMaintenanceWindow(serverId, {
+        title: title.trim(),
+        starts_at: new Date(startsAt).toISOString(),
+        duration_min: duration,
+        recurrence,
+      });
+      setTitle("");
+      setStartsAt("");
+      refresh();
+    } catch (err) {
+      toast((err as Error).message, "error");
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const remove = async (w: MaintenanceWindow) => {
+    if (!confirm(t.maintenanceDeleteConfirm)) return;
+    await api.deleteMaintenanceWindow(serverId, w.id).catch((err) => toast((err as Error).message, "error"));
+    refresh();
+  };
+
+  const recurrenceLabel: Record<MaintenanceRecurrence, string> = {
+    "": t.maintenanceOnce,
+    daily: t.maintenanceDaily,
+    weekly: t.maintenanceWeekly,
+  };
+
+  const input = "bg-white/5 border border-white/10 rounded-xl px-3 py-2 text-sm text-foreground outline-none focus:border-neon-green/50 transition-all placeholder:text-muted-foreground";
+
+  return (
+    <div className="flex flex-col gap-4">
+      <p className="text-xs text-muted-foreground uppercase tracking-wide flex items-center gap-2">
+        <Wrench className="w-3.5 h-3.5" /> {t.serverDetailMaintenance}
+      </p>
+
+      {isLoading ? (
+        <div className="h-16 bg-white/5 rounded-xl animate-pulse" />
+      ) : windows.length === 0 ? (
+        <p className="text-sm text-muted-foreground">{t.maintenanceNone}</p>
+      ) : (
+        <div className="flex flex-col gap-1">
+          {windows.map((w) => (
+            <div key={w.id} className="flex items-center gap-3 py-1.5 px-2 rounded-lg hover:bg-white/5 transition-colors text-sm">
+              <span className={cn("w-2 h-2 rounded-full flex-shrink-0", w.active ? "bg-amber-400 animate-pulse" : w.next ? "bg-amber-400/40" : "bg-muted-foreground/30")} />
+              <span className="font-medium text-foreground truncate">{w.title || t.serverDetailMaintenance}</span>
+              <span className="text-xs text-muted-foreground">{recurrenceLabel[w.recurrence]} · {w.duration_min} {t.maintenanceMinutes}</span>
+              <span className="flex-1 text-xs text-muted-foreground/60 font-mono truncate">
+                {w.active ? t.maintenanceActive : w.next ? new Date(w.next.from).toLocaleString() : t.maintenancePast}
+              </span>
+              {canManage && (
+                <button onClick={() => remove(w)} className="text-muted-foreground hover:text-red-400 transition-colors">
+                  <Trash2 className="w-3.5 h-3.5" />
+                </button>
+              )}
+            </div>
+          ))}
+        </div>
+      )}
+
+      {canManage && (
+        <div className="flex flex-wrap gap-2">
+          <input className={cn(input, "flex-1 min-w-[8rem]")} placeholder={t.maintenanceTitle} value={title} onChange={(e) => setTitle(e.target.value)} maxLength={200} />
+          <input type="datetime-local" className={cn(input, "w-52")} value={startsAt} onChange={(e) => setStartsAt(e.target.value)} />
+          <input type="number" className={cn(input, "w-24")} min={1} value={duration} onChange={(e) => setDuration(Number(e.target.value))} title={t.maintenanceMinutes} />
+          <select className={cn(input, "w-36")} value={recurrence} onChange={(e) => setRecurrence(e.target.value as MaintenanceRecurrence)}>
+            {(["", "daily", "weekly"] as const).map((r) => (
+              <option key={r} value={r}>{recurrenceLabel[r]}</option>
+            ))}
+          </select>
+          <button
+            onClick={create}
+            disabled={busy || !startsAt}
+            className="flex items-center gap-1.5 px-3 py-2 rounded-xl text-xs border border-white/10 hover:border-white/25 hover:bg-white/5 text-muted-foreground hover:text-foreground transition-all disabled:opacity-50"
+          >
+            <Plus className="w-3.5 h-3.5" /> {t.maintenanceAdd}
+          </button>
+        </div>
+      )}
+    </div>
+  );
+}
@@ -5,6 +5,7 @@ import { useLanguage } from "@/contexts/LanguageContext";
 
 interface StatusIndicatorProps {
   online: boolean;
+  maintenance?: boolean; // offline during a scheduled maintenance window
   size?: "sm" | "md" | "lg";
   showLabel?: boolean;
 }
@@ -17,17 +18,19 @@ const sizeMap = {
 
 export default function StatusIndicator({
   online,
+  maintenance = false,
   size = "md",
   showLabel = false,
 }: StatusIndicatorProps) {
   const { t } = useLanguage();
+  const inMaintenance = !online && maintenance;
   return (
     <div className="flex items-center gap-2">
       <span
         className={cn(
           "rounded-full status-dot flex-shrink-0",
           sizeMap[size],
-          online ? "status-dot-online" : "status-dot-offline"
+          online ? "status-dot-online" : inMaintenance ? "bg-amber-400" : "status-dot-offline"
         )}
       />
       {showLabel && (
@@ -36,10 +39,12 @@ export default function StatusIndicator({
             "text-xs font-semibold uppercase tracking-wider px-2 py-0.5 rounded-full",
             online
               ? "bg-neon-green/10 text-neon-green border border-neon-green/25"
+              : inMaintenance
+              ? "bg-amber-400/10 text-amber-400 border border-amber-400/25"
               : "bg-red-500/10 text-red-400 border border-red-500/20"
           )}
         >
-          {online ? t.statusOnline : t.statusOffline}
+          {online ? t.statusOnline : inMaintenance ? t.statusMaintenance : t.statusOffline}
         </span>
       )}
     </div>
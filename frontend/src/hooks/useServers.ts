@@ -47,6 +47,32 @@ export function useServerPlayers(id: number, enabled: boolean) {
   });
 }
 
+export function useServerRegions(id: number) {
+  return useQuery({
+    queryKey: ["regions", id],
+    queryFn: () => api.getServerRegions(id),
+    refetchInterval: 60_000,
+    staleTime: 30_000,
+  });
+}
+
+export function useMaintenanceWindows(id: number) {
+  return useQuery({
+    queryKey: ["maintenance", id],
+    queryFn: () => api.getMaintenanceWindows(id),
+    staleTime: 30_000,
+  });
+}
+
+export function useServerIncidents(id: number, period: "7d" | "30d" | "90d" = "30d") {
+  return useQuery({
+    queryKey: ["incidents", id, period],
+    queryFn: () => api.getServerIncidents(id, period),
+    refetchInterval: 60_000,
+    staleTime: 30_000,
+  });
+}
+
 export function useLeaderboard(id: number, period: "7d" | "30d" | "all" = "7d") {
   return useQuery({
     queryKey: ["leaderboard", id, period],
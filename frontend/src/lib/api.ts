@@ -1,4 +1,4 @@
-import type { Server, ServerPlayer, PlayerHistory, LeaderboardEntry, Stats, AuthResponse, User, NewsItem, AdminServer, UptimeData, GlobalLeaderboardEntry, PlayerProfile, AuditPage, AlertConfig, DiscordConfig, UserSession } from "@/types/server";
+import type { Server, ServerPlayer, ProbeResult, RegionStatus, PollAgent, ServerIncident, IncidentPage, MaintenanceWindow, MaintenanceRecurrence, PlayerHistory, LeaderboardEntry, Stats, AuthResponse, User, NewsItem, AdminServer, UptimeData, GlobalLeaderboardEntry, PlayerProfile, AuditPage, AlertConfig, AlertRule, AlertRuleInput, DiscordConfig, UserSession } from "@/types/server";
 
 export interface VRisingPlayer {
   name: string;
@@ -168,6 +168,20 @@ export interface SystemHealth {
   db_in_use: number;
   db_idle: number;
   go_version: string;
+  poller: PollerStats | null;
+}
+
+export interface PollerStats {
+  scheduler: {
+    servers: number;
+    queue_depth: number;
+    overdue: number;
+    in_flight: number;
+    jobs_backlog: number;
+    dropped: number;
+    rate_limited: number;
+  };
+  protocols: Record<string, { polls: number; failures: number; avg_ms: number; last_ms: number; max_ms: number }>;
 }
 
 export interface AdminSiteSettings {
@@ -206,6 +220,9 @@ export interface AdminSiteSettings {
   vrising_free_plot_icon_set?: boolean;
   vrising_hide_admins?: boolean;
   maintenance_mode?: boolean;
+  history_raw_retention_days?: number;
+  history_5m_retention_days?: number;
+  history_1h_retention_days?: number;
 }
 
 export interface SSLStatus {
@@ -238,6 +255,9 @@ export const api = {
   createServer: (data: Partial<Server>) =>
     fetchJSON<Server>("/api/v1/servers", { method: "POST", body: JSON.stringify(data) }),
 
+  probeServer: (ip: string, port: number) =>
+    fetchJSON<ProbeResult[]>("/api/v1/servers/probe", { method: "POST", body: JSON.stringify({ ip, port }) }),
+
   updateServer: (id: number, data: Partial<Server>) =>
     fetchJSON<Server>(`/api/v1/servers/${id}`, { method: "PUT", body: JSON.stringify(data) }),
 
@@ -249,6 +269,20 @@ export const api = {
     fetchJSON<ServerPlayer[]>(`/api/v1/servers/${id}/players`),
   getLeaderboard: (id: number, period: "7d" | "30d" | "all" = "7d") =>
     fetchJSON<LeaderboardEntry[]>(`/api/v1/servers/${id}/leaderboard?period=${period}`),
+  getServerRegions: (id: number) =>
+    fetchJSON<RegionStatus[]>(`/api/v1/servers/${id}/regions`),
+  getServerIncidents: (id: number, period: "7d" | "30d" | "90d" = "30d", page = 1) =>
+    fetchJSON<IncidentPage>(`/api/v1/servers/${id}/incidents?period=${period}&page=${page}`),
+  ackIncident: (serverId: number, incidentId: number, note: string) =>
+    fetchJSON<ServerIncident>(`/api/v1/servers/${serverId}/incidents/${incidentId}/ack`, { method: "POST", body: JSON.stringify({ note }) }),
+  getMaintenanceWindows: (id: number) =>
+    fetchJSON<MaintenanceWindow[]>(`/api/v1/servers/${id}/maintenance`),
+  createMaintenanceWindow: (id: number, data: { title: string; starts_at: string; duration_min: number; recurrence: MaintenanceRecurrence }) =>
+    fetchJSON<MaintenanceWindow>(`/api/v1/servers/${id}/maintenance`, { method: "POST", body: JSON.stringify(data) }),
+  deleteMaintenanceWindow: (id: number, windowId: number) =>
+    fetchJSON<{ ok: boolean }>(`/api/v1/servers/${id}/maintenance/${windowId}`, { method: "DELETE" }),
+  getAdminIncidents: (period: "7d" | "30d" | "90d" = "30d", page = 1, openOnly = false) =>
+    fetchJSON<IncidentPage>(`/api/v1/admin/incidents?period=${period}&page=${page}${openOnly ? "&open=true" : ""}`),
 
   // Setup
   setupStatus: () => fetchJSON<{ needed: boolean }>("/api/v1/setup/status"),
@@ -344,6 +378,14 @@ export const api = {
     fetchJSON<AlertConfig>(`/api/v1/admin/alerts/${serverID}`),
   updateAlertConfig: (serverID: number, data: { enabled: boolean; tg_chat_id: string; offline_timeout: number; notify_online?: boolean; email_to?: string }) =>
     fetchJSON<AlertConfig>(`/api/v1/admin/alerts/${serverID}`, { method: "PUT", body: JSON.stringify(data) }),
+  getAlertRules: (serverID: number) =>
+    fetchJSON<AlertRule[]>(`/api/v1/admin/alerts/${serverID}/rules`),
+  createAlertRule: (serverID: number, data: AlertRuleInput) =>
+    fetchJSON<AlertRule>(`/api/v1/admin/alerts/${serverID}/rules`, { method: "POST", body: JSON.stringify(data) }),
+  updateAlertRule: (serverID: number, ruleID: number, data: AlertRuleInput) =>
+    fetchJSON<AlertRule>(`/api/v1/admin/alerts/${serverID}/rules/${ruleID}`, { method: "PUT", body: JSON.stringify(data) }),
+  deleteAlertRule: (serverID: number, ruleID: number) =>
+    fetchJSON<{ ok: boolean }>(`/api/v1/admin/alerts/${serverID}/rules/${ruleID}`, { method: "DELETE" }),
 
   // Password reset (admin)
   generateResetToken: (userID: number) =>
@@ -389,6 +431,7 @@ export const api = {
   exportServersUrl: () => `${BASE}/api/v1/admin/export/servers.csv`,
   exportPlayersUrl: () => `${BASE}/api/v1/admin/export/players.csv`,
   exportAuditUrl:   () => `${BASE}/api/v1/admin/export/audit.csv`,
+  slaReportUrl:     (month: string) => `${BASE}/api/v1/admin/reports/sla?month=${month}&format=csv`,
 
   // Bulk operations (admin)
   bulkUsers: (action: string, ids: number[]): Promise<{ ok: boolean; count: number }> =>
@@ -397,7 +440,7 @@ export const api = {
     fetchJSON("/api/v1/admin/servers/bulk", { method: "POST", body: JSON.stringify({ action, ids }) }),
 
   // Update settings with registration_enabled and news webhook
-  updateSettingsFull: (data: { site_name?: string; logo_data?: string; app_url?: string; steam_api_key?: string; registration_enabled?: boolean; news_webhook_url?: string; news_role_id?: string; news_tg_bot_token?: string; news_tg_chat_id?: string; news_tg_thread_id?: string; force_https?: boolean; default_theme?: string; discord_bot_token?: string; discord_app_id?: string; discord_proxy?: string; discord_embed_config?: string; discord_alert_channel_id?: string; discord_refresh_interval?: number; vrising_map_enabled?: boolean; vrising_map_url?: string; vrising_map_image?: string; vrising_world_x_min?: number; vrising_world_x_max?: number; vrising_world_z_min?: number; vrising_world_z_max?: number; vrising_castle_icon?: string; vrising_player_icon?: string; vrising_free_plot_icon?: string; vrising_hide_admins?: boolean; ssl_mode?: string; ssl_domain?: string; maintenance_mode?: boolean }) =>
+  updateSettingsFull: (data: { site_name?: string; logo_data?: string; app_url?: string; steam_api_key?: string; registration_enabled?: boolean; news_webhook_url?: string; news_role_id?: string; news_tg_bot_token?: string; news_tg_chat_id?: string; news_tg_thread_id?: string; force_https?: boolean; default_theme?: string; discord_bot_token?: string; discord_app_id?: string; discord_proxy?: string; discord_embed_config?: string; discord_alert_channel_id?: string; discord_refresh_interval?: number; vrising_map_enabled?: boolean; vrising_map_url?: string; vrising_map_image?: string; vrising_world_x_min?: number; vrising_world_x_max?: number; vrising_world_z_min?: number; vrising_world_z_max?: number; vrising_castle_icon?: string; vrising_player_icon?: string; vrising_free_plot_icon?: string; vrising_hide_admins?: boolean; ssl_mode?: string; ssl_domain?: string; maintenance_mode?: boolean; history_raw_retention_days?: number; history_5m_retention_days?: number; history_1h_retention_days?: number }) =>
     fetchJSON<SiteSettings>("/api/v1/admin/settings", { method: "PUT", body: JSON.stringify(data) }),
 
   testNewsWebhook: () => fetchJSON<{ ok: boolean }>("/api/v1/admin/news/webhook/test", { method: "POST" }),
@@ -425,6 +468,15 @@ export const api = {
   getDashboard: () => fetchJSON<DashboardData>("/api/v1/admin/dashboard"),
   getSystemHealth: () => fetchJSON<SystemHealth>("/api/v1/admin/health"),
 
+  // Remote polling agents (admin)
+  getAgents: () => fetchJSON<PollAgent[]>("/api/v1/admin/agents"),
+  createAgent: (name: string, region: string) =>
+    fetchJSON<{ agent: PollAgent; token: string }>("/api/v1/admin/agents", { method: "POST", body: JSON.stringify({ name, region }) }),
+  updateAgent: (id: number, data: { name: string; region: string; enabled: boolean }) =>
+    fetchJSON<PollAgent>(`/api/v1/admin/agents/${id}`, { method: "PUT", body: JSON.stringify(data) }),
+  deleteAgent: (id: number) =>
+    fetchJSON<{ message: string }>(`/api/v1/admin/agents/${id}`, { method: "DELETE" }),
+
   // V Rising moderation (admin)
   getVRisingBans: (serverID: number) =>
     fetchJSON<VRisingBan[]>(`/api/v1/admin/vrising/${serverID}/bans`),
@@ -23,6 +23,7 @@ export const translations = {
     deleteServer: "Delete server",
     statusOnline: "ONLINE",
     statusOffline: "OFFLINE",
+    statusMaintenance: "MAINTENANCE",
     chartNoData: "No data yet",
     chartLoading: "Loading…",
     rconConnected: "CONNECTED",
@@ -36,8 +37,19 @@ export const translations = {
     fieldPort: "Port",
     fieldGameType: "Game Type",
     fieldRcon: "RCON Password (optional)",
+    fieldRconPort: "RCON port",
+    fieldRconPortAuto: "auto",
     fieldDiscordColor: "Discord Embed Color (optional)",
     fieldDiscordColorHint: "Custom color for this server's Discord embed. Leave blank to use status-based color (green/red).",
+    fieldTrackedRules: "Tracked server rules",
+    btnDetectGame: "Detect",
+    probeNoResponse: "No supported protocol answered on this address",
+    probeFound: "Detected",
+    fieldTrackedRulesHint: "Comma-separated A2S rule names to store and show in Discord, e.g. wipe,mapsize.",
+    fieldQueryPort: "Query port",
+    fieldPollInterval: "Poll, active (s)",
+    fieldEmptyPollInterval: "Poll, empty (s)",
+    fieldPollHint: "Query port defaults to the game port (Valheim, ARK, Arma 3 often answer on port+1). Leave intervals blank for 10s with players and 60s when empty.",
     fieldRequired: "IP is required",
     btnCancel: "Cancel",
     btnAdding: "Saving…",
@@ -243,6 +255,16 @@ export const translations = {
     favUnpin: "Unpin",
     // Offline time
     offlineSince: "offline",
+    failureTimeout: "No response (timeout)",
+    failureRefused: "Connection refused",
+    failureUnreachable: "Host unreachable",
+    failureDns: "Hostname not resolved",
+    failureParse: "Invalid response",
+    // A2S server info badges
+    serverVersion: "Version",
+    serverPassword: "Password protected",
+    serverVAC: "VAC secured",
+    serverBots: "Bots",
     // Copy / Share
     copyIp: "Copy IP",
     toastCopied: "IP copied!",
@@ -451,6 +473,7 @@ export const translations = {
     adminEmbedFieldAverage24h: "Average 24h",
     adminEmbedFieldUniqueToday: "Unique Players Today",
     adminEmbedFieldPlayerList: "Player List",
+    adminEmbedFieldRules: "Server Rules",
     // V Rising Map
     adminSettingsVRisingMap: "V Rising — Live Map",
     adminSettingsVRisingMapEnabled: "Enable Live Map tab",
@@ -506,6 +529,11 @@ export const translations = {
     adminMaintenanceMode: "Maintenance mode",
     adminMaintenanceModeHint: "Closes the site for all non-admin users",
     adminMaintenanceModeWarn: "Warning: enabling this will hide the site from all non-admin users",
+    adminHistoryRetention: "History retention",
+    adminHistoryRetentionRaw: "Raw points, days",
+    adminHistoryRetention5m: "5-minute rollups, days",
+    adminHistoryRetention1h: "Hourly rollups, days",
+    adminHistoryRetentionHint: "Charts for longer periods use rollups automatically. 0 keeps data forever.",
     // Overview / Dashboard
     adminOverviewPlayersOnline: "Players Online",
     adminOverviewServersOnline: "Servers Online",
@@ -552,6 +580,24 @@ export const translations = {
     adminHealthDbIdle: "idle",
     adminHealthGcRuns: "GC runs",
     adminHealthGoVersion: "Go",
+    adminHealthPollQueue: "Poll queue",
+    adminHealthPollInFlight: "in flight",
+    adminHealthPollDeferred: "Deferred polls",
+    adminHealthPollDropped: "workers busy",
+    adminHealthPollRateLimited: "per-IP limit",
+    adminHealthPollLatency: "Poll latency",
+    adminAgentsTitle: "Polling agents",
+    adminAgentsHint: "Agents run this binary with AGENT_API_URL and AGENT_TOKEN in other regions. A server is marked offline only when most fresh vantage points agree.",
+    adminAgentsName: "Agent name",
+    adminAgentsRegion: "Region",
+    adminAgentsAdd: "Add agent",
+    adminAgentsTokenOnce: "Agent token — shown only once, copy it now:",
+    adminAgentsTokenCopied: "Token copied!",
+    adminAgentsLastSeen: "seen",
+    adminAgentsNeverSeen: "never connected",
+    adminAgentsEnable: "Enable",
+    adminAgentsDisable: "Disable",
+    adminAgentsDeleteConfirm: "Delete agent",
     // Backup & Restore
     adminBackup: "Backup & Restore",
     adminBackupHint: "Exports all data (users, servers, settings, news) to a JSON file. The file contains all secrets — keep it secure.",
@@ -568,6 +614,26 @@ export const translations = {
     alertsNotifyOnline: "Notify when server comes back online",
     alertsEmailTo: "Email for alerts",
     alertsEmailToHint: "Requires SMTP_HOST env var to be set on the server.",
+    // Alert rules
+    alertRulesTitle: "Alert rules",
+    alertRulesNone: "No rules yet",
+    alertRuleAdd: "Add rule",
+    alertRuleName: "Name (optional)",
+    alertRuleThreshold: "Threshold",
+    alertRuleDuration: "Holds for, min",
+    alertRuleCooldown: "Cooldown, min",
+    alertRuleWebhook: "Discord webhook URL (optional)",
+    alertRuleTargetsHint: "Without targets the rule uses the chat and email above.",
+    alertRuleMinutes: "min",
+    alertRuleLastFired: "Last fired",
+    alertRuleDeleteConfirm: "Delete this alert rule?",
+    alertRulePlayersAbove: "Players above",
+    alertRulePlayersBelow: "Players below",
+    alertRulePingAbove: "Ping above, ms",
+    alertRuleServerFull: "Server is full",
+    alertRuleMapChanged: "Map changed",
+    alertRuleNameChanged: "Name changed",
+    alertRuleVersionChanged: "Version changed",
     // Bulk actions
     bulkSelected: (n: number) => `${n} selected`,
     bulkDelete: "Delete selected",
@@ -580,6 +646,7 @@ export const translations = {
     exportServers: "Export servers",
     exportPlayers: "Export players",
     exportAudit: "Export audit",
+    exportSLAReport: "SLA report (last month)",
     // MOTD
     serverMotd: "MOTD",
     // Compare
@@ -641,6 +708,27 @@ export const translations = {
     serverDetailOnlinePlayers: "Online players",
     serverDetailHistory: "Player history",
     serverDetailLeaderboard: "Top players",
+    serverDetailRegions: "Regions",
+    serverDetailIncidents: "Incidents",
+    incidentsCount: "Incidents",
+    incidentsDowntime: "Downtime",
+    incidentsMTTR: "MTTR",
+    incidentsMTBF: "MTBF",
+    incidentsNone: "No outages in this period",
+    incidentsOngoing: "ongoing",
+    incidentsAck: "Acknowledge",
+    incidentsAckNotePrompt: "Note (optional):",
+    serverDetailMaintenance: "Maintenance",
+    maintenanceNone: "No maintenance windows scheduled",
+    maintenanceOnce: "One-off",
+    maintenanceDaily: "Daily",
+    maintenanceWeekly: "Weekly",
+    maintenanceMinutes: "min",
+    maintenanceActive: "in progress",
+    maintenancePast: "finished",
+    maintenanceTitle: "Reason (optional)",
+    maintenanceAdd: "Schedule",
+    maintenanceDeleteConfirm: "Delete this maintenance window?",
   },
 
   ru: {
@@ -665,6 +753,7 @@ export const translations = {
     deleteServer: "Удалить сервер",
     statusOnline: "ОНЛАЙН",
     statusOffline: "ОФЛАЙН",
+    statusMaintenance: "ОБСЛУЖИВАНИЕ",
     chartNoData: "Нет данных",
     chartLoading: "Загрузка…",
     rconConnected: "ПОДКЛЮЧЁН",
@@ -678,8 +767,19 @@ export const translations = {
     fieldPort: "Порт",
     fieldGameType: "Тип игры",
     fieldRcon: "Пароль RCON (необязательно)",
+    fieldRconPort: "Порт RCON",
+    fieldRconPortAuto: "авто",
     fieldDiscordColor: "Цвет эмбеда Discord (необязательно)",
     fieldDiscordColorHint: "Свой цвет для эмбеда этого сервера. Оставьте пустым — цвет будет по статусу (зелёный/красный).",
+    fieldTrackedRules: "Отслеживаемые правила",
+    btnDetectGame: "Определить",
+    probeNoResponse: "Ни один поддерживаемый протокол не ответил по этому адресу",
+    probeFound: "Обнаружено",
+    fieldTrackedRulesHint: "Имена A2S-правил через запятую — сохраняются и показываются в Discord, например wipe,mapsize.",
+    fieldQueryPort: "Порт опроса",
+    fieldPollInterval: "Опрос, игра (с)",
+    fieldEmptyPollInterval: "Опрос, пусто (с)",
+    fieldPollHint: "По умолчанию опрашивается игровой порт (Valheim, ARK, Arma 3 часто отвечают на порту +1). Пустые интервалы — 10 с при игроках и 60 с без них.",
     fieldRequired: "IP обязателен",
     btnCancel: "Отмена",
     btnAdding: "Сохранение…",
@@ -885,6 +985,16 @@ export const translations = {
     favUnpin: "Открепить",
     // Offline time
     offlineSince: "офлайн",
+    failureTimeout: "Нет ответа (таймаут)",
+    failureRefused: "Соединение отклонено",
+    failureUnreachable: "Хост недоступен",
+    failureDns: "Имя хоста не найдено",
+    failureParse: "Некорректный ответ",
+    // A2S server info badges
+    serverVersion: "Версия",
+    serverPassword: "Вход по паролю",
+    serverVAC: "Защита VAC",
+    serverBots: "Боты",
     // Copy / Share
     copyIp: "Скопировать IP",
     toastCopied: "IP скопирован!",
@@ -1093,6 +1203,7 @@ export const translations = {
     adminEmbedFieldAverage24h: "Среднее 24ч",
     adminEmbedFieldUniqueToday: "Игроков сегодня",
     adminEmbedFieldPlayerList: "Список игроков",
+    adminEmbedFieldRules: "Правила сервера",
     // V Rising Map
     adminSettingsVRisingMap: "V Rising — Живая карта",
     adminSettingsVRisingMapEnabled: "Включить вкладку «Живая карта»",
@@ -1148,6 +1259,11 @@ export const translations = {
     adminMaintenanceMode: "Режим обслуживания",
     adminMaintenanceModeHint: "Закрывает сайт для всех не-администраторов",
     adminMaintenanceModeWarn: "Внимание: включение скроет сайт от всех не-администраторов",
+    adminHistoryRetention: "Хранение истории",
+    adminHistoryRetentionRaw: "Сырые точки, дней",
+    adminHistoryRetention5m: "5-минутные агрегаты, дней",
+    adminHistoryRetention1h: "Часовые агрегаты, дней",
+    adminHistoryRetentionHint: "Графики за длинные периоды автоматически строятся по агрегатам. 0 — хранить бессрочно.",
     // Overview / Dashboard
     adminOverviewPlayersOnline: "Игроков онлайн",
     adminOverviewServersOnline: "Серверов онлайн",
@@ -1194,6 +1310,24 @@ export const translations = {
     adminHealthDbIdle: "свободно",
     adminHealthGcRuns: "запусков GC",
     adminHealthGoVersion: "Go",
+    adminHealthPollQueue: "Очередь опроса",
+    adminHealthPollInFlight: "опрашивается",
+    adminHealthPollDeferred: "Отложено опросов",
+    adminHealthPollDropped: "воркеры заняты",
+    adminHealthPollRateLimited: "лимит на IP",
+    adminHealthPollLatency: "Задержка опроса",
+    adminAgentsTitle: "Агенты опроса",
+    adminAgentsHint: "Агенты — этот же бинарник с AGENT_API_URL и AGENT_TOKEN в других регионах. Сервер считается офлайн, только если так видит большинство свежих точек наблюдения.",
+    adminAgentsName: "Имя агента",
+    adminAgentsRegion: "Регион",
+    adminAgentsAdd: "Добавить",
+    adminAgentsTokenOnce: "Токен агента — показывается один раз, скопируйте его сейчас:",
+    adminAgentsTokenCopied: "Токен скопирован!",
+    adminAgentsLastSeen: "был",
+    adminAgentsNeverSeen: "ещё не подключался",
+    adminAgentsEnable: "Включить",
+    adminAgentsDisable: "Выключить",
+    adminAgentsDeleteConfirm: "Удалить агента",
     // Backup & Restore
     adminBackup: "Бекап и восстановление",
     adminBackupHint: "Экспортирует все данные (пользователи, серверы, настройки, новости) в JSON-файл. Файл содержит все секреты — храните его в безопасности.",
@@ -1210,6 +1344,26 @@ export const translations = {
     alertsNotifyOnline: "Уведомлять о восстановлении сервера",
     alertsEmailTo: "Email для уведомлений",
     alertsEmailToHint: "Требует наличия переменной SMTP_HOST на сервере.",
+    // Alert rules
+    alertRulesTitle: "Правила оповещений",
+    alertRulesNone: "Правил пока нет",
+    alertRuleAdd: "Добавить правило",
+    alertRuleName: "Название (необязательно)",
+    alertRuleThreshold: "Порог",
+    alertRuleDuration: "Держится, мин",
+    alertRuleCooldown: "Пауза, мин",
+    alertRuleWebhook: "URL Discord webhook (необязательно)",
+    alertRuleTargetsHint: "Без получателей правило использует чат и email выше.",
+    alertRuleMinutes: "мин",
+    alertRuleLastFired: "Последнее срабатывание",
+    alertRuleDeleteConfirm: "Удалить это правило?",
+    alertRulePlayersAbove: "Игроков больше",
+    alertRulePlayersBelow: "Игроков меньше",
+    alertRulePingAbove: "Пинг выше, мс",
+    alertRuleServerFull: "Сервер заполнен",
+    alertRuleMapChanged: "Смена карты",
+    alertRuleNameChanged: "Смена имени",
+    alertRuleVersionChanged: "Смена версии",
     // Bulk actions
     bulkSelected: (n: number) => `Выбрано: ${n}`,
     bulkDelete: "Удалить выбранные",
@@ -1222,6 +1376,7 @@ export const translations = {
     exportServers: "Экспорт серверов",
     exportPlayers: "Экспорт игроков",
     exportAudit: "Экспорт аудита",
+    exportSLAReport: "SLA-отчёт (прошлый месяц)",
     // MOTD
     serverMotd: "MOTD",
     // Compare
@@ -1283,6 +1438,27 @@ export const translations = {
     serverDetailOnlinePlayers: "Игроки онлайн",
     serverDetailHistory: "История игроков",
     serverDetailLeaderboard: "Топ игроков",
+    serverDetailRegions: "Регионы",
+    serverDetailIncidents: "Инциденты",
+    incidentsCount: "Инцидентов",
+    incidentsDowntime: "Простой",
+    incidentsMTTR: "MTTR",
+    incidentsMTBF: "MTBF",
+    incidentsNone: "Простоев за период не было",
+    incidentsOngoing: "продолжается",
+    incidentsAck: "Разобрано",
+    incidentsAckNotePrompt: "Комментарий (необязательно):",
+    serverDetailMaintenance: "Обслуживание",
+    maintenanceNone: "Плановых работ нет",
+    maintenanceOnce: "Разово",
+    maintenanceDaily: "Ежедневно",
+    maintenanceWeekly: "Еженедельно",
+    maintenanceMinutes: "мин",
+    maintenanceActive: "идут сейчас",
+    maintenancePast: "завершены",
+    maintenanceTitle: "Причина (необязательно)",
+    maintenanceAdd: "Запланировать",
+    maintenanceDeleteConfirm: "Удалить окно плановых работ?",
   },
 } as const;
 
@@ -1,11 +1,20 @@
 import { type ClassValue, clsx } from "clsx";
 import { twMerge } from "tailwind-merge";
-import type { GameType } from "@/types/server";
+import type { GameType, FailureReason } from "@/types/server";
 
 export function cn(...inputs: ClassValue[]) {
   return twMerge(clsx(inputs));
 }
 
+// Translation keys for poll failure reasons
+export const FAILURE_REASON_KEYS = {
+  timeout: "failureTimeout",
+  refused: "failureRefused",
+  unreachable: "failureUnreachable",
+  dns: "failureDns",
+  parse: "failureParse",
+} as const satisfies Record<FailureReason, string>;
+
 export function formatPlayers(now: number, max: number): string {
   return `${now} / ${max}`;
 }
@@ -31,6 +40,8 @@ export function buildJoinLink(gameType: GameType, ip: string, port: number, disp
       return `minecraft://?addExternalServer=Server|${host}:${port}`;
     case "fivem":
       return `fivem://connect/${host}:${port}`;
+    case "redm":
+      return `redm://connect/${host}:${port}`;
     case "samp":
       return `samp://${host}:${port}`;
     case "source":
@@ -67,6 +78,7 @@ export const GAME_META: Record<GameType, GameMeta> = {
   minecraft:         { label: "Minecraft Java",    icon: "⛏️",  defaultPort: 25565, protocol: "minecraft" },
   minecraft_bedrock: { label: "Minecraft Bedrock", icon: "📦", defaultPort: 19132, protocol: "minecraft" },
   fivem:             { label: "FiveM / GTA V",     icon: "🚗", defaultPort: 30120, protocol: "fivem",      steamAppId: 271590 },
+  redm:              { label: "RedM / RDR2",       icon: "🤠", defaultPort: 30120, protocol: "fivem",      steamAppId: 1174180 },
   samp:              { label: "SA-MP / open.mp",   icon: "🏙️",  defaultPort: 7777,  protocol: "samp",       steamAppId: 12120 },
   terraria:          { label: "Terraria",          icon: "🌳", defaultPort: 7777,  protocol: "terraria",   steamAppId: 105600 },
 };
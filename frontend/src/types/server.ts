@@ -8,12 +8,37 @@ export interface ServerStatus {
   server_name?: string;
   ping_ms: number;
   last_update: string;
+  bots?: number;
+  server_type?: string;
+  environment?: string;
+  password?: boolean;
+  vac?: boolean;
+  version?: string;
+  game_port?: number;
+  steam_id?: string;
+  keywords?: string;
+  app_id?: number;
+  version_changed_at?: string | null;
+  motd_spans?: string; // JSON: MOTDSpan[]
+  fail_count?: number;
+  failure_reason?: FailureReason;
+}
+
+export type FailureReason = "timeout" | "refused" | "unreachable" | "dns" | "parse";
+
+export interface MOTDSpan {
+  text: string;
+  color?: string;
+  bold?: boolean;
+  italic?: boolean;
+  underlined?: boolean;
+  strikethrough?: boolean;
+  obfuscated?: boolean;
 }
 
 export interface AlertConfig {
   id: number;
   server_id: number;
-  threshold_cpu: number;
   offline_timeout: number;
   tg_chat_id: string;
   enabled: boolean;
@@ -21,11 +46,38 @@ export interface AlertConfig {
   email_to: string;
 }
 
+export type AlertRuleCondition =
+  | "players_above"
+  | "players_below"
+  | "ping_above"
+  | "server_full"
+  | "map_changed"
+  | "name_changed"
+  | "version_changed";
+
+export interface AlertRule {
+  id: number;
+  server_id: number;
+  name: string;
+  condition: AlertRuleCondition;
+  threshold: number;
+  duration_min: number;
+  cooldown_min: number;
+  enabled: boolean;
+  tg_chat_id: string;
+  email_to: string;
+  webhook_url: string;
+  last_fired_at: string | null;
+}
+
+export type AlertRuleInput = Omit<AlertRule, "id" | "server_id" | "last_fired_at">;
+
 export type GameType =
   | "source"
   | "minecraft"
   | "minecraft_bedrock"
   | "fivem"
+  | "redm"
   | "samp"
   | "valheim"
   | "terraria"
@@ -47,10 +99,23 @@ export interface Server {
   country_name?: string;
   owner_id?: number;
   discord_color?: string;
+  tracked_rules?: string;
+  query_port?: number; // 0 = query the game port
+  rcon_port?: number; // 0 = protocol default
+  poll_interval?: number; // seconds while players are online, 0 = default
+  empty_poll_interval?: number; // seconds while empty, 0 = default
   created_at: string;
   updated_at: string;
   status?: ServerStatus;
   alert_config?: AlertConfig;
+  rules?: ServerRule[];
+  in_maintenance?: boolean;
+}
+
+export interface ServerRule {
+  key: string;
+  value: string;
+  updated_at: string;
 }
 
 export interface NewsItem {
@@ -76,8 +141,77 @@ export function parseTags(item: NewsItem): string[] {
   return item.tags?.split(",").map(s => s.trim()).filter(Boolean) ?? [];
 }
 
+export interface ProbeResult {
+  protocol: string;
+  games: GameType[];
+  port: number;
+  server_name: string;
+  map: string;
+  players_now: number;
+  players_max: number;
+  ping_ms: number;
+  version?: string;
+}
+
+// Status of a server as seen by one remote polling agent
+export interface RegionStatus {
+  id: number;
+  server_id: number;
+  agent_id: number;
+  region: string;
+  online_status: boolean;
+  players_now: number;
+  ping_ms: number;
+  failure_reason?: FailureReason;
+  checked_at: string;
+}
+
+// Outage from a confirmed offline transition until recovery
+export interface ServerIncident {
+  id: number;
+  server_id: number;
+  server_title?: string;
+  started_at: string;
+  resolved_at: string | null;
+  duration_sec: number;
+  cause: FailureReason | "";
+  ack_user_id: number | null;
+  ack_username: string;
+  ack_at: string | null;
+  ack_note: string;
+}
+
+export interface IncidentStats {
+  period: "7d" | "30d" | "90d";
+  incidents: number;
+  open: number;
+  downtime_seconds: number;
+  mttr_seconds: number | null;
+  mtbf_seconds: number | null;
+}
+
+export interface IncidentPage {
+  items: ServerIncident[];
+  total: number;
+  stats: IncidentStats;
+}
+
+export interface PollAgent {
+  id: number;
+  name: string;
+  region: string;
+  enabled: boolean;
+  last_seen_at: string | null;
+  last_ip: string;
+  created_at: string;
+}
+
 export interface ServerPlayer {
   name: string;
+  score?: number;
+  ping?: number;
+  duration?: number; // seconds connected, as reported by the server
+  identifiers?: string[];
 }
 
 export interface PlayerHistory {
@@ -144,10 +278,38 @@ export interface AuthResponse {
   user: User;
 }
 
+export type MaintenanceRecurrence = "" | "daily" | "weekly";
+
+export interface MaintenanceWindow {
+  id: number;
+  server_id: number;
+  title: string;
+  starts_at: string;
+  duration_min: number;
+  recurrence: MaintenanceRecurrence;
+  created_by: number;
+  created_at: string;
+  active: boolean;
+  next: { from: string; to: string } | null;
+}
+
+export interface UptimeDay {
+  date: string;
+  observed_seconds: number;
+  downtime_seconds: number;
+  uptime: number | null;
+}
+
 export interface UptimeData {
-  uptime_24h: number;
-  total: number;
-  online: number;
+  period: string;
+  from: string;
+  to: string;
+  uptime: number | null;
+  uptime_24h?: number;
+  observed_seconds: number;
+  downtime_seconds: number;
+  excluded_seconds: number;
+  days: UptimeDay[];
 }
 
 export interface GlobalLeaderboardEntry {